@@ -4,7 +4,13 @@ import (
 	"log"
 	"mantest/backend/internal/database"
 	"mantest/backend/internal/handlers"
+	"mantest/backend/internal/middleware"
+	"mantest/backend/internal/models"
+	"mantest/backend/internal/services"
+	"mantest/backend/internal/validation"
 	"net/http"
+	"os"
+	"strings"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -17,7 +23,18 @@ func main() {
 	}
 
 	database.InitDB()
+	if err := services.InitJWT(); err != nil {
+		log.Fatalf("JWT configuration error: %v", err)
+	}
+	services.StartSLAScheduler()
+	validation.UseJSONFieldNames()
+
 	router := gin.Default()
+	// Client IPs drive login throttling, so forwarding headers are only
+	// believed from the proxies listed in TRUSTED_PROXIES.
+	if err := router.SetTrustedProxies(trustedProxies()); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
 
 	config := cors.DefaultConfig()
 	config.AllowAllOrigins = true
@@ -30,23 +47,98 @@ func main() {
 		c.String(http.StatusOK, "API Server is running!")
 	})
 
+	router.GET("/.well-known/jwks.json", handlers.JWKSHandler)
+
 	api := router.Group("/api")
 	{
 		api.POST("/login", handlers.LoginHandler)
-		api.GET("/user/profile", handlers.GetUserProfileHandler)
-		api.POST("/request", handlers.CreateManpowerRequestHandler)
-        
-        api.GET("/masterdata", handlers.GetMasterDataHandler)
-        
-        admin := api.Group("/admin")
-        {
-            admin.GET("/employees", handlers.GetEmployeesHandler)
-            admin.POST("/employees", handlers.CreateEmployeeHandler) 
-        }
+		api.POST("/login/mfa", handlers.VerifyMFALoginHandler)
+		api.POST("/login/mfa/enroll", handlers.BeginLoginMFAEnrollmentHandler)
+		api.POST("/login/mfa/enroll/confirm", handlers.ConfirmLoginMFAEnrollmentHandler)
+		api.POST("/refresh", handlers.RefreshTokenHandler)
+		api.POST("/password/reset", handlers.ResetPasswordHandler)
+
+		protected := api.Group("")
+		protected.Use(middleware.AuthRequired())
+		{
+			protected.POST("/logout", handlers.LogoutHandler)
+
+			protected.GET("/mfa", handlers.GetMFAStatusHandler)
+			protected.POST("/mfa/enroll", handlers.BeginMFAEnrollmentHandler)
+			protected.POST("/mfa/enroll/confirm", handlers.ConfirmMFAEnrollmentHandler)
+			protected.POST("/mfa/disable", handlers.DisableMFAHandler)
+			protected.POST("/mfa/recovery-codes", handlers.RegenerateRecoveryCodesHandler)
+
+			protected.GET("/me", middleware.RequirePermission(models.PermProfileRead), handlers.GetCurrentUserHandler)
+			protected.GET("/notifications", middleware.RequirePermission(models.PermProfileRead), handlers.GetNotificationsHandler)
+			protected.POST("/notifications/:notificationId/read", middleware.RequirePermission(models.PermProfileRead), handlers.MarkNotificationReadHandler)
+			protected.POST("/request", middleware.RequirePermission(models.PermRequestsCreate), handlers.CreateManpowerRequestHandler)
+			protected.GET("/requests", middleware.RequirePermission(models.PermRequestsRead), handlers.GetManpowerRequestsHandler)
+			protected.GET("/requests/drafts", middleware.RequirePermission(models.PermRequestsCreate), handlers.GetDraftsHandler)
+			protected.POST("/requests/drafts", middleware.RequirePermission(models.PermRequestsCreate), handlers.CreateDraftHandler)
+			protected.GET("/requests/drafts/:draftId", middleware.RequirePermission(models.PermRequestsCreate), handlers.GetDraftHandler)
+			protected.PUT("/requests/drafts/:draftId", middleware.RequirePermission(models.PermRequestsCreate), handlers.UpdateDraftHandler)
+			protected.DELETE("/requests/drafts/:draftId", middleware.RequirePermission(models.PermRequestsCreate), handlers.DeleteDraftHandler)
+			protected.POST("/requests/drafts/:draftId/submit", middleware.RequirePermission(models.PermRequestsCreate), handlers.SubmitDraftHandler)
+			protected.GET("/requests/:id", middleware.RequirePermission(models.PermRequestsRead), handlers.GetManpowerRequestHandler)
+			protected.GET("/requests/:id/workflow", middleware.RequirePermission(models.PermRequestsRead), handlers.GetRequestWorkflowHandler)
+			protected.POST("/requests/:id/decisions", middleware.RequirePermission(models.PermRequestsApprove), handlers.CreateDecisionHandler)
+			protected.POST("/requests/:id/withdraw", middleware.RequirePermission(models.PermRequestsCreate), handlers.WithdrawRequestHandler)
+			protected.PUT("/requests/:id/resubmit", middleware.RequirePermission(models.PermRequestsCreate), handlers.ResubmitRequestHandler)
+			protected.POST("/requests/:id/fill", middleware.RequirePermission(models.PermRequestsFulfill), handlers.FillRequestHandler)
+			protected.POST("/requests/:id/lines/:lineId/fill", middleware.RequirePermission(models.PermRequestsFulfill), handlers.FillRequestLineHandler)
+
+			protected.GET("/delegations", middleware.RequirePermission(models.PermRequestsApprove), handlers.GetDelegationsHandler)
+			protected.POST("/delegations", middleware.RequirePermission(models.PermRequestsApprove), handlers.CreateDelegationHandler)
+			protected.DELETE("/delegations/:delegationId", middleware.RequirePermission(models.PermRequestsApprove), handlers.CancelDelegationHandler)
+
+			protected.GET("/masterdata", middleware.RequirePermission(models.PermMasterDataRead), handlers.GetMasterDataHandler)
+			protected.GET("/holidays", middleware.RequirePermission(models.PermMasterDataRead), handlers.GetHolidaysHandler)
+
+			admin := protected.Group("/admin")
+			{
+				admin.GET("/employees", middleware.RequirePermission(models.PermEmployeesRead), handlers.GetEmployeesHandler)
+				admin.POST("/employees", middleware.RequirePermission(models.PermEmployeesWrite), handlers.CreateEmployeeHandler)
+				admin.POST("/employees/:employeeId/password-reset", middleware.RequirePermission(models.PermEmployeesWrite), handlers.ForcePasswordResetHandler)
+				admin.POST("/employees/:employeeId/sessions/revoke", middleware.RequirePermission(models.PermEmployeesWrite), handlers.RevokeEmployeeSessionsHandler)
+				admin.POST("/employees/:employeeId/unlock", middleware.RequirePermission(models.PermEmployeesWrite), handlers.UnlockEmployeeHandler)
+				admin.POST("/employees/:employeeId/mfa/reset", middleware.RequirePermission(models.PermEmployeesWrite), handlers.ResetEmployeeMFAHandler)
+
+				admin.GET("/login-events", middleware.RequirePermission(models.PermAuditRead), handlers.GetLoginEventsHandler)
+
+				admin.GET("/roles/permissions", middleware.RequirePermission(models.PermRolesManage), handlers.GetRolePermissionsHandler)
+				admin.PUT("/roles/:roleName/permissions", middleware.RequirePermission(models.PermRolesManage), handlers.SetRolePermissionsHandler)
+				admin.PUT("/roles/:roleName/mfa", middleware.RequirePermission(models.PermRolesManage), handlers.SetRoleMFAHandler)
+
+				admin.GET("/routing-rules", middleware.RequirePermission(models.PermWorkflowManage), handlers.GetRoutingRulesHandler)
+				admin.POST("/routing-rules", middleware.RequirePermission(models.PermWorkflowManage), handlers.CreateRoutingRuleHandler)
+				admin.POST("/routing-rules/dry-run", middleware.RequirePermission(models.PermWorkflowManage), handlers.DryRunRoutingHandler)
+				admin.PUT("/routing-rules/:ruleId", middleware.RequirePermission(models.PermWorkflowManage), handlers.UpdateRoutingRuleHandler)
+				admin.DELETE("/routing-rules/:ruleId", middleware.RequirePermission(models.PermWorkflowManage), handlers.DeleteRoutingRuleHandler)
+
+				admin.GET("/document-sequences", middleware.RequirePermission(models.PermSequencesManage), handlers.GetDocumentSequencesHandler)
+				admin.PUT("/document-sequences/:docType", middleware.RequirePermission(models.PermSequencesManage), handlers.SaveDocumentSequenceHandler)
+
+				admin.POST("/holidays", middleware.RequirePermission(models.PermCalendarManage), handlers.CreateHolidayHandler)
+				admin.POST("/holidays/import", middleware.RequirePermission(models.PermCalendarManage), handlers.ImportHolidaysHandler)
+				admin.PUT("/holidays/:holidayId", middleware.RequirePermission(models.PermCalendarManage), handlers.UpdateHolidayHandler)
+				admin.DELETE("/holidays/:holidayId", middleware.RequirePermission(models.PermCalendarManage), handlers.DeleteHolidayHandler)
+			}
+		}
 	}
 
 	log.Println("Server is running on :8080")
 	if err := router.Run(":8080"); err != nil {
 		log.Fatalf("Server failed to start: %v", err)
 	}
-}
\ No newline at end of file
+}
+
+func trustedProxies() []string {
+	var proxies []string
+	for _, p := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			proxies = append(proxies, p)
+		}
+	}
+	return proxies
+}
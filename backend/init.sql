@@ -1,8 +1,19 @@
 CREATE TABLE roles (
     role_id SERIAL PRIMARY KEY,
     role_name VARCHAR(50) UNIQUE NOT NULL,
+    mfa_required BOOLEAN NOT NULL DEFAULT FALSE,
     status VARCHAR(10) DEFAULT 'Active'
 );
+CREATE TABLE permissions (
+    permission_id SERIAL PRIMARY KEY,
+    permission_code VARCHAR(100) UNIQUE NOT NULL,
+    description VARCHAR(255)
+);
+CREATE TABLE role_permissions (
+    role_id INT REFERENCES roles(role_id) ON DELETE CASCADE NOT NULL,
+    permission_id INT REFERENCES permissions(permission_id) ON DELETE CASCADE NOT NULL,
+    PRIMARY KEY (role_id, permission_id)
+);
 CREATE TABLE departments (
     dept_id SERIAL PRIMARY KEY,
     dept_name VARCHAR(100) UNIQUE NOT NULL
@@ -46,6 +57,18 @@ CREATE TABLE education_levels (
     edu_name VARCHAR(100) UNIQUE NOT NULL
 );
 
+-- Submissions look master data up by name, case-insensitively.
+CREATE INDEX idx_departments_name_upper ON departments (UPPER(dept_name));
+CREATE INDEX idx_positions_name_upper ON positions (UPPER(pos_name));
+CREATE INDEX idx_sections_name_upper ON sections (UPPER(section_name));
+CREATE INDEX idx_employment_types_name_upper ON employment_types (UPPER(et_name));
+CREATE INDEX idx_contract_types_name_upper ON contract_types (UPPER(ct_name));
+CREATE INDEX idx_request_reasons_name_upper ON request_reasons (UPPER(rr_name));
+CREATE INDEX idx_genders_name_upper ON genders (UPPER(gender_name));
+CREATE INDEX idx_nationalities_name_upper ON nationalities (UPPER(nat_name));
+CREATE INDEX idx_experiences_name_upper ON experiences (UPPER(exp_name));
+CREATE INDEX idx_education_levels_name_upper ON education_levels (UPPER(edu_name));
+
 CREATE TABLE employees (
     employee_id VARCHAR(50) PRIMARY KEY,
     first_name VARCHAR(100) NOT NULL,
@@ -55,22 +78,95 @@ CREATE TABLE employees (
     profile_image TEXT,
     pos_id INT REFERENCES positions(pos_id),
     dept_id INT REFERENCES departments(dept_id),
+    section_id INT REFERENCES sections(section_id),
     role_id INT REFERENCES roles(role_id) NOT NULL,
+    supervisor_id VARCHAR(50) REFERENCES employees(employee_id),
+    password_reset_required BOOLEAN NOT NULL DEFAULT FALSE,
     status VARCHAR(10) DEFAULT 'Active'
 );
 
+CREATE TABLE password_reset_tokens (
+    token_id SERIAL PRIMARY KEY,
+    employee_id VARCHAR(50) REFERENCES employees(employee_id) NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    issued_by VARCHAR(50) REFERENCES employees(employee_id),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE auth_sessions (
+    session_id VARCHAR(64) PRIMARY KEY,
+    employee_id VARCHAR(50) REFERENCES employees(employee_id) NOT NULL,
+    ip_address VARCHAR(64),
+    user_agent TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    revoked_reason VARCHAR(100)
+);
+CREATE INDEX idx_auth_sessions_employee ON auth_sessions(employee_id);
+
+CREATE TABLE refresh_tokens (
+    token_id SERIAL PRIMARY KEY,
+    session_id VARCHAR(64) REFERENCES auth_sessions(session_id) ON DELETE CASCADE NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE employee_mfa (
+    employee_id VARCHAR(50) PRIMARY KEY REFERENCES employees(employee_id),
+    totp_secret VARCHAR(64) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    enrolled_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE mfa_recovery_codes (
+    code_id SERIAL PRIMARY KEY,
+    employee_id VARCHAR(50) REFERENCES employees(employee_id) NOT NULL,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_mfa_recovery_codes_employee ON mfa_recovery_codes(employee_id);
+
+CREATE TABLE login_throttle (
+    throttle_key VARCHAR(150) PRIMARY KEY,
+    failure_count INT NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP WITH TIME ZONE,
+    locked_until TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE login_events (
+    event_id SERIAL PRIMARY KEY,
+    email VARCHAR(100) NOT NULL,
+    employee_id VARCHAR(50) REFERENCES employees(employee_id),
+    success BOOLEAN NOT NULL,
+    failure_reason VARCHAR(50),
+    ip_address VARCHAR(64),
+    user_agent TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_login_events_email ON login_events(email, created_at);
+CREATE INDEX idx_login_events_ip ON login_events(ip_address, created_at);
+
 CREATE TABLE manpower_requests ( 
     request_id SERIAL PRIMARY KEY, 
-    doc_number VARCHAR(50) UNIQUE NOT NULL, 
+    doc_number VARCHAR(50) UNIQUE, 
     employee_id VARCHAR(50) REFERENCES employees(employee_id) NOT NULL, 
     doc_date DATE NOT NULL DEFAULT CURRENT_DATE, 
-    requesting_dept_id INT REFERENCES departments(dept_id) NOT NULL, 
-    requesting_pos_id INT REFERENCES positions(pos_id) NOT NULL, 
-    employment_type_id INT REFERENCES employment_types(et_id) NOT NULL, 
-    contract_type_id INT REFERENCES contract_types(ct_id) NOT NULL, 
-    reason_id INT REFERENCES request_reasons(rr_id) NOT NULL,
-    required_position_code VARCHAR(50) NOT NULL, 
-    required_position_name VARCHAR(100) NOT NULL, 
+    requesting_dept_id INT REFERENCES departments(dept_id), 
+    requesting_pos_id INT REFERENCES positions(pos_id), 
+    employment_type_id INT REFERENCES employment_types(et_id), 
+    contract_type_id INT REFERENCES contract_types(ct_id), 
+    reason_id INT REFERENCES request_reasons(rr_id),
+    required_position_code VARCHAR(50) NOT NULL DEFAULT '', 
+    required_position_name VARCHAR(100) NOT NULL DEFAULT '', 
     min_age INT, 
     max_age INT, 
     gender_id INT REFERENCES genders(gender_id), 
@@ -78,25 +174,225 @@ CREATE TABLE manpower_requests (
     experience_id INT REFERENCES experiences(exp_id), 
     education_level_id INT REFERENCES education_levels(edu_id), 
     special_qualifications TEXT, 
+    headcount INT NOT NULL DEFAULT 1,
     current_status VARCHAR(50) DEFAULT 'รอ HR พิจารณา', 
+    lifecycle_status VARCHAR(20) NOT NULL DEFAULT 'DRAFT' CHECK (lifecycle_status IN ('DRAFT', 'SUBMITTED', 'IN_APPROVAL', 'RETURNED', 'APPROVED', 'REJECTED', 'CANCELLED', 'FILLED')),
     target_hire_date DATE, 
     approval_history_id INT, 
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP, 
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    -- Drafts may be saved half filled in, and discarded that way; everything
+    -- else past a draft must be complete.
+    CHECK (lifecycle_status IN ('DRAFT', 'CANCELLED') OR (
+        doc_number IS NOT NULL AND requesting_dept_id IS NOT NULL AND requesting_pos_id IS NOT NULL
+        AND employment_type_id IS NOT NULL AND contract_type_id IS NOT NULL AND reason_id IS NOT NULL
+    ))
+);
+CREATE INDEX idx_manpower_requests_drafts ON manpower_requests(employee_id, lifecycle_status);
+
+-- The positions asked for on a request. The request's own position and
+-- headcount summarise these: the first line's position and the total quantity.
+CREATE TABLE manpower_request_lines (
+    line_id SERIAL PRIMARY KEY,
+    request_id INT REFERENCES manpower_requests(request_id) ON DELETE CASCADE NOT NULL,
+    line_no INT NOT NULL,
+    pos_id INT REFERENCES positions(pos_id),
+    position_code VARCHAR(50) NOT NULL DEFAULT '',
+    position_name VARCHAR(100) NOT NULL DEFAULT '',
+    -- A draft may leave the quantity out (0); submission requires one.
+    quantity INT NOT NULL DEFAULT 1 CHECK (quantity >= 0),
+    qualifications TEXT,
+    target_hire_date DATE,
+    filled_quantity INT NOT NULL DEFAULT 0 CHECK (filled_quantity >= 0 AND filled_quantity <= quantity),
+    UNIQUE (request_id, line_no)
+);
+
+CREATE TABLE request_line_fills (
+    fill_id SERIAL PRIMARY KEY,
+    line_id INT REFERENCES manpower_request_lines(line_id) ON DELETE CASCADE NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    filled_by VARCHAR(50) REFERENCES employees(employee_id) NOT NULL,
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_request_line_fills_line ON request_line_fills(line_id, created_at);
+
+ALTER TABLE departments ADD COLUMN manager_id VARCHAR(50) REFERENCES employees(employee_id);
+
+CREATE TABLE workflow_step_definitions (
+    definition_id SERIAL PRIMARY KEY,
+    step_code VARCHAR(30) UNIQUE NOT NULL,
+    step_name VARCHAR(100) NOT NULL,
+    step_order INT NOT NULL,
+    pending_status VARCHAR(50) NOT NULL,
+    approver_type VARCHAR(20) NOT NULL CHECK (approver_type IN ('DEPT_MANAGER', 'POSITION', 'EMPLOYEE', 'GROUP')),
+    approver_dept_id INT REFERENCES departments(dept_id),
+    approver_pos_id INT REFERENCES positions(pos_id),
+    approver_employee_id VARCHAR(50) REFERENCES employees(employee_id),
+    completion_policy VARCHAR(10) NOT NULL DEFAULT 'ANY' CHECK (completion_policy IN ('ALL', 'ANY', 'QUORUM')),
+    required_approvals INT CHECK (required_approvals > 0),
+    sla_business_days INT CHECK (sla_business_days > 0),
+    is_default BOOLEAN NOT NULL DEFAULT TRUE,
+    status VARCHAR(10) DEFAULT 'Active'
+);
+
+CREATE TABLE workflow_step_group_members (
+    step_code VARCHAR(30) REFERENCES workflow_step_definitions(step_code) ON DELETE CASCADE NOT NULL,
+    employee_id VARCHAR(50) REFERENCES employees(employee_id) NOT NULL,
+    PRIMARY KEY (step_code, employee_id)
+);
+
+CREATE TABLE workflow_routing_rules (
+    rule_id SERIAL PRIMARY KEY,
+    rule_name VARCHAR(150) NOT NULL,
+    priority INT NOT NULL DEFAULT 100,
+    dept_id INT REFERENCES departments(dept_id),
+    employment_type_id INT REFERENCES employment_types(et_id),
+    contract_type_id INT REFERENCES contract_types(ct_id),
+    reason_id INT REFERENCES request_reasons(rr_id),
+    min_headcount INT,
+    max_headcount INT,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('SKIP_STEP', 'ADD_STEP')),
+    step_code VARCHAR(30) REFERENCES workflow_step_definitions(step_code) NOT NULL,
+    status VARCHAR(10) DEFAULT 'Active',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
+CREATE TABLE request_approval_steps (
+    step_id SERIAL PRIMARY KEY,
+    request_id INT REFERENCES manpower_requests(request_id) ON DELETE CASCADE NOT NULL,
+    round INT NOT NULL DEFAULT 1,
+    step_order INT NOT NULL,
+    step_code VARCHAR(30) NOT NULL,
+    step_name VARCHAR(100) NOT NULL,
+    pending_status VARCHAR(50) NOT NULL,
+    completion_policy VARCHAR(10) NOT NULL DEFAULT 'ANY',
+    required_approvals INT NOT NULL DEFAULT 1,
+    status VARCHAR(20) NOT NULL DEFAULT 'Waiting',
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    sla_business_days INT,
+    due_at TIMESTAMP WITH TIME ZONE,
+    reminder_sent_at TIMESTAMP WITH TIME ZONE,
+    escalation_level INT NOT NULL DEFAULT 0,
+    UNIQUE (request_id, round, step_order)
+);
+CREATE INDEX idx_request_approval_steps_due ON request_approval_steps(status, due_at);
+
+CREATE TABLE request_step_approvers (
+    step_id INT REFERENCES request_approval_steps(step_id) ON DELETE CASCADE NOT NULL,
+    approver_id VARCHAR(50) REFERENCES employees(employee_id) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'Pending',
+    acted_by VARCHAR(50) REFERENCES employees(employee_id),
+    decided_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (step_id, approver_id)
+);
+CREATE INDEX idx_request_step_approvers_approver ON request_step_approvers(approver_id, status);
+
+CREATE TABLE approver_delegations (
+    delegation_id SERIAL PRIMARY KEY,
+    employee_id VARCHAR(50) REFERENCES employees(employee_id) NOT NULL,
+    delegate_id VARCHAR(50) REFERENCES employees(employee_id) NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    reason VARCHAR(255),
+    status VARCHAR(10) NOT NULL DEFAULT 'Active',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (employee_id <> delegate_id),
+    CHECK (end_date >= start_date)
+);
+CREATE INDEX idx_approver_delegations_employee ON approver_delegations(employee_id, status);
+CREATE INDEX idx_approver_delegations_delegate ON approver_delegations(delegate_id, status);
+
 CREATE TABLE approval_history ( 
 history_id SERIAL PRIMARY KEY, 
 request_id INT REFERENCES manpower_requests(request_id) NOT NULL, 
+step_id INT REFERENCES request_approval_steps(step_id),
 approver_id VARCHAR(50) REFERENCES employees(employee_id) NOT NULL, 
+on_behalf_of VARCHAR(50) REFERENCES employees(employee_id),
 decision VARCHAR(50) NOT NULL,
  notes TEXT, 
 approval_time TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP, 
 step_name VARCHAR(100) NOT NULL, 
 status VARCHAR(10) DEFAULT 'Active');
+CREATE INDEX idx_approval_history_request ON approval_history(request_id, approval_time);
+
+CREATE TABLE public_holidays (
+    holiday_id SERIAL PRIMARY KEY,
+    holiday_date DATE UNIQUE NOT NULL,
+    holiday_name VARCHAR(150) NOT NULL,
+    source VARCHAR(10) NOT NULL DEFAULT 'MANUAL',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Numbering schemes for documents. pattern mixes literal text with the
+-- tokens {YYYY}, {YY}, {MM}, {DD} and {SEQ:n}; the running number restarts
+-- every reset_period.
+CREATE TABLE document_sequences (
+    doc_type VARCHAR(50) PRIMARY KEY,
+    pattern VARCHAR(100) NOT NULL,
+    reset_period VARCHAR(10) NOT NULL DEFAULT 'MONTHLY' CHECK (reset_period IN ('NEVER', 'YEARLY', 'MONTHLY', 'DAILY')),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE document_sequence_counters (
+    doc_type VARCHAR(50) REFERENCES document_sequences(doc_type) ON DELETE CASCADE NOT NULL,
+    period_key VARCHAR(10) NOT NULL,
+    last_value INT NOT NULL,
+    PRIMARY KEY (doc_type, period_key)
+);
+
+CREATE TABLE request_status_transitions (
+    transition_id SERIAL PRIMARY KEY,
+    request_id INT REFERENCES manpower_requests(request_id) ON DELETE CASCADE NOT NULL,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    actor_id VARCHAR(50) REFERENCES employees(employee_id),
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_request_status_transitions_request ON request_status_transitions(request_id, created_at);
+
+CREATE TABLE notifications (
+    notification_id SERIAL PRIMARY KEY,
+    employee_id VARCHAR(50) REFERENCES employees(employee_id) NOT NULL,
+    request_id INT REFERENCES manpower_requests(request_id) ON DELETE CASCADE,
+    kind VARCHAR(30) NOT NULL,
+    message TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    read_at TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX idx_notifications_employee ON notifications(employee_id, read_at);
 
 INSERT INTO roles (role_name) VALUES ('Admin'), ('Approve'), ('User');
 
+INSERT INTO permissions (permission_code, description) VALUES
+('profile:read', 'ดูข้อมูลโปรไฟล์ของตนเอง'),
+('masterdata:read', 'ดูข้อมูลหลัก'),
+('employees:read', 'ดูรายชื่อพนักงาน'),
+('employees:write', 'เพิ่ม/แก้ไขข้อมูลพนักงาน'),
+('roles:manage', 'จัดการสิทธิ์ของบทบาท'),
+('workflow:manage', 'จัดการเส้นทางการอนุมัติ'),
+('calendar:manage', 'จัดการปฏิทินวันหยุด'),
+('sequences:manage', 'จัดการรูปแบบเลขที่เอกสาร'),
+('audit:read', 'ดูประวัติการเข้าสู่ระบบ'),
+('requests:create', 'สร้างใบร้องขอกำลังคน'),
+('requests:create_on_behalf', 'สร้างใบร้องขอกำลังคนแทนพนักงานอื่น'),
+('requests:read', 'ดูใบร้องขอกำลังคน'),
+('requests:read_all', 'ดูใบร้องขอกำลังคนทั้งหมด'),
+('requests:approve', 'อนุมัติใบร้องขอกำลังคน'),
+('requests:fulfill', 'ปิดใบร้องขอกำลังคนเมื่อจัดหาบุคลากรได้แล้ว');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.role_id, p.permission_id
+FROM roles r
+JOIN permissions p ON (
+    (r.role_name = 'Admin' AND p.permission_code IN ('profile:read', 'masterdata:read', 'employees:read', 'employees:write', 'roles:manage', 'workflow:manage', 'calendar:manage', 'sequences:manage', 'audit:read', 'requests:read', 'requests:read_all', 'requests:create', 'requests:create_on_behalf', 'requests:fulfill'))
+    OR (r.role_name = 'Approve' AND p.permission_code IN ('profile:read', 'masterdata:read', 'requests:read', 'requests:approve'))
+    OR (r.role_name = 'User' AND p.permission_code IN ('profile:read', 'masterdata:read', 'requests:create', 'requests:read'))
+);
+
 INSERT INTO departments (dept_name) VALUES ('ฝ่ายบริหาร'), ('ฝ่ายทรัพยากรบุคคล'), ('ฝ่ายการตลาด'), ('ฝ่ายเทคโนโลยีสารสนเทศ'), ('ฝ่ายผลิต'), ('ฝ่ายบัญชี'), ('ฝ่ายจัดซื้อ');
 INSERT INTO positions (pos_name) VALUES ('ผู้จัดการ'), ('เจ้าหน้าที่ HR'), ('นักการตลาด'), ('โปรแกรมเมอร์'), ('พนักงานทั่วไป'), ('นักบัญชี'), ('เจ้าหน้าที่จัดซื้อ');
 
@@ -149,7 +445,82 @@ INSERT INTO education_levels (edu_name) VALUES
 ('ปริญญาเอก'), 
 ('ไม่จำกัดวุฒิ');
 
-INSERT INTO employees (employee_id, first_name, last_name, email, password, pos_id, dept_id, role_id) VALUES
-('E001', 'แอดมิน', 'ทดสอบ', 'admin@email.com', '1234', 1, 1, 1),
-('E002', 'อนุมัติ', 'ทดสอบ', 'approve@email.com', '1234', 1, 1, 2),
-('E003', 'ผู้ใช้งาน', 'ทดสอบ', 'user@email.com', '1234', 5, 4, 3);
\ No newline at end of file
+INSERT INTO employees (employee_id, first_name, last_name, email, password, pos_id, dept_id, section_id, role_id) VALUES
+('E001', 'แอดมิน', 'ทดสอบ', 'admin@email.com', '1234', 1, 1, 1, 1),
+('E002', 'อนุมัติ', 'ทดสอบ', 'approve@email.com', '1234', 1, 1, 1, 2),
+('E003', 'ผู้ใช้งาน', 'ทดสอบ', 'user@email.com', '1234', 5, 4, 5, 3),
+('E004', 'เอชอาร์', 'ทดสอบ', 'hr@email.com', '1234', 2, 2, 6, 2),
+('E005', 'ผู้จัดการไอที', 'ทดสอบ', 'itmanager@email.com', '1234', 1, 4, 5, 2),
+('E006', 'ซีทีโอ', 'ทดสอบ', 'cto@email.com', '1234', 1, 1, 1, 2),
+('E007', 'เอชอาร์สอง', 'ทดสอบ', 'hr2@email.com', '1234', 2, 2, 6, 2);
+
+UPDATE departments SET manager_id = 'E002' WHERE dept_name = 'ฝ่ายบริหาร';
+UPDATE departments SET manager_id = 'E004' WHERE dept_name = 'ฝ่ายทรัพยากรบุคคล';
+UPDATE departments SET manager_id = 'E005' WHERE dept_name = 'ฝ่ายเทคโนโลยีสารสนเทศ';
+
+UPDATE employees SET supervisor_id = 'E002' WHERE employee_id IN ('E004', 'E005', 'E006');
+UPDATE employees SET supervisor_id = 'E004' WHERE employee_id = 'E007';
+
+INSERT INTO workflow_step_definitions (step_code, step_name, step_order, pending_status, approver_type, approver_dept_id, approver_pos_id, approver_employee_id, completion_policy, sla_business_days) VALUES
+('MANAGER', 'ผู้จัดการฝ่ายพิจารณา', 10, 'รอผู้จัดการพิจารณา', 'DEPT_MANAGER', NULL, NULL, NULL, 'ANY', 2),
+('HR', 'HR พิจารณา', 20, 'รอ HR พิจารณา', 'POSITION',
+    (SELECT dept_id FROM departments WHERE dept_name = 'ฝ่ายทรัพยากรบุคคล'),
+    (SELECT pos_id FROM positions WHERE pos_name = 'เจ้าหน้าที่ HR'), NULL, 'ALL', 3),
+('CEO', 'CEO พิจารณา', 30, 'รอ CEO พิจารณา', 'EMPLOYEE', NULL, NULL, 'E002', 'ANY', 3);
+
+INSERT INTO workflow_step_definitions (step_code, step_name, step_order, pending_status, approver_type, approver_employee_id, is_default, sla_business_days) VALUES
+('CTO', 'CTO พิจารณา', 25, 'รอ CTO พิจารณา', 'EMPLOYEE', 'E006', FALSE, 2);
+
+INSERT INTO workflow_step_definitions (step_code, step_name, step_order, pending_status, approver_type, completion_policy, required_approvals, is_default, sla_business_days) VALUES
+('EXECUTIVE', 'คณะผู้บริหารพิจารณา', 35, 'รอคณะผู้บริหารพิจารณา', 'GROUP', 'QUORUM', 2, FALSE, 5);
+
+INSERT INTO workflow_step_group_members (step_code, employee_id) VALUES
+('EXECUTIVE', 'E002'),
+('EXECUTIVE', 'E005'),
+('EXECUTIVE', 'E006');
+
+INSERT INTO workflow_routing_rules (rule_name, priority, dept_id, employment_type_id, reason_id, action, step_code) VALUES
+('จ้างชั่วคราวไม่ต้องผ่าน CEO', 10, NULL, (SELECT et_id FROM employment_types WHERE et_name = 'ชั่วคราว'), NULL, 'SKIP_STEP', 'CEO'),
+('แทนตำแหน่งที่ว่างไม่ต้องผ่าน HR', 20, NULL, NULL, (SELECT rr_id FROM request_reasons WHERE rr_name = 'แทนตำแหน่งที่ว่าง'), 'SKIP_STEP', 'HR'),
+('ฝ่ายไอทีต้องผ่าน CTO', 30, (SELECT dept_id FROM departments WHERE dept_name = 'ฝ่ายเทคโนโลยีสารสนเทศ'), NULL, NULL, 'ADD_STEP', 'CTO');
+
+INSERT INTO workflow_routing_rules (rule_name, priority, min_headcount, action, step_code) VALUES
+('ขอกำลังคนตั้งแต่ 5 อัตราต้องผ่านคณะผู้บริหาร', 40, 5, 'ADD_STEP', 'EXECUTIVE');
+
+INSERT INTO document_sequences (doc_type, pattern, reset_period) VALUES
+('MANPOWER_REQUEST', 'PQ{YY}{MM}{SEQ:4}', 'MONTHLY');
+
+-- Fixed-date Thai public holidays. Buddhist holidays follow the lunar
+-- calendar and substitution days are announced each year, so those are
+-- added through the admin holiday import.
+INSERT INTO public_holidays (holiday_date, holiday_name) VALUES
+('2026-01-01', 'วันขึ้นปีใหม่'),
+('2026-04-06', 'วันจักรี'),
+('2026-04-13', 'วันสงกรานต์'),
+('2026-04-14', 'วันสงกรานต์'),
+('2026-04-15', 'วันสงกรานต์'),
+('2026-05-01', 'วันแรงงานแห่งชาติ'),
+('2026-05-04', 'วันฉัตรมงคล'),
+('2026-06-03', 'วันเฉลิมพระชนมพรรษาสมเด็จพระนางเจ้าฯ พระบรมราชินี'),
+('2026-07-28', 'วันเฉลิมพระชนมพรรษาพระบาทสมเด็จพระเจ้าอยู่หัว'),
+('2026-08-12', 'วันแม่แห่งชาติ'),
+('2026-10-13', 'วันนวมินทรมหาราช'),
+('2026-10-23', 'วันปิยมหาราช'),
+('2026-12-05', 'วันพ่อแห่งชาติ'),
+('2026-12-10', 'วันรัฐธรรมนูญ'),
+('2026-12-31', 'วันสิ้นปี'),
+('2027-01-01', 'วันขึ้นปีใหม่'),
+('2027-04-06', 'วันจักรี'),
+('2027-04-13', 'วันสงกรานต์'),
+('2027-04-14', 'วันสงกรานต์'),
+('2027-04-15', 'วันสงกรานต์'),
+('2027-05-01', 'วันแรงงานแห่งชาติ'),
+('2027-05-04', 'วันฉัตรมงคล'),
+('2027-06-03', 'วันเฉลิมพระชนมพรรษาสมเด็จพระนางเจ้าฯ พระบรมราชินี'),
+('2027-07-28', 'วันเฉลิมพระชนมพรรษาพระบาทสมเด็จพระเจ้าอยู่หัว'),
+('2027-08-12', 'วันแม่แห่งชาติ'),
+('2027-10-13', 'วันนวมินทรมหาราช'),
+('2027-10-23', 'วันปิยมหาราช'),
+('2027-12-05', 'วันพ่อแห่งชาติ'),
+('2027-12-10', 'วันรัฐธรรมนูญ'),
+('2027-12-31', 'วันสิ้นปี');
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+	"log"
 	"mantest/backend/internal/models"
 	"mantest/backend/internal/services"
 	"net/http"
@@ -26,7 +28,17 @@ func CreateEmployeeHandler(c *gin.Context) {
 
 	err := services.CreateNewEmployee(&req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		var invalid *services.InvalidFieldsError
+		if errors.As(err, &invalid) {
+			respondFieldErrors(c, invalid.Fields)
+			return
+		}
+		if errors.Is(err, services.ErrDuplicateEmployee) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Failed to create employee %s: %v", req.EmployeeID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create employee"})
 		return
 	}
 
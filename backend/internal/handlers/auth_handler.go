@@ -1,9 +1,14 @@
 package handlers
 
 import (
+	"errors"
+	"log"
+	"math"
+	"mantest/backend/internal/middleware"
 	"mantest/backend/internal/models"
 	"mantest/backend/internal/services"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,15 +20,92 @@ func LoginHandler(c *gin.Context) {
 		return
 	}
 
-	token, roleName, email, err := services.Authenticate(req.Email, req.Password)
+	response, err := services.Authenticate(req.Email, req.Password, clientInfo(c))
+	var throttled *services.LoginThrottledError
+	if errors.As(err, &throttled) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, gin.H{"message": "Too many failed login attempts. Please try again later."})
+		return
+	}
+	if errors.Is(err, services.ErrPasswordResetRequired) {
+		c.JSON(http.StatusForbidden, gin.H{"message": "Password reset required: please use the reset token provided by your administrator"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"message": "Authentication Fail: Please check user or Password"})
 		return
 	}
 
-	c.JSON(http.StatusOK, models.AuthResponse{
-		Token: token,
-		Role:  roleName,
-		Email: email,
+	c.JSON(http.StatusOK, response)
+}
+
+func clientInfo(c *gin.Context) models.ClientInfo {
+	return models.ClientInfo{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
+
+func RefreshTokenHandler(c *gin.Context) {
+	var req models.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	response, err := services.RefreshSession(req.RefreshToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized", "message": "Refresh token is invalid, expired or revoked"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func LogoutHandler(c *gin.Context) {
+	claims, _ := middleware.GetAuthClaims(c)
+	if err := services.RevokeSession(claims.SessionID, "logout"); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Logged out successfully!",
+	})
+}
+
+func JWKSHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, services.GetJWKS())
+}
+
+func RevokeEmployeeSessionsHandler(c *gin.Context) {
+	revoked, err := services.RevokeAllSessions(c.Param("employeeId"), "revoked by admin")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Sessions revoked successfully!",
+		"revoked": revoked,
+	})
+}
+
+func UnlockEmployeeHandler(c *gin.Context) {
+	if err := services.UnlockEmployeeAccount(c.Param("employeeId")); err != nil {
+		if errors.Is(err, services.ErrEmployeeNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+			return
+		}
+		log.Printf("Failed to unlock employee %s: %v", c.Param("employeeId"), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unlock account"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Account unlocked successfully!",
 	})
-}
\ No newline at end of file
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"mantest/backend/internal/middleware"
+	"mantest/backend/internal/models"
+	"mantest/backend/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetDelegationsHandler(c *gin.Context) {
+	claims, _ := middleware.GetAuthClaims(c)
+
+	delegations, err := services.GetDelegations(claims.EmployeeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch delegations"})
+		return
+	}
+	c.JSON(http.StatusOK, delegations)
+}
+
+func CreateDelegationHandler(c *gin.Context) {
+	claims, _ := middleware.GetAuthClaims(c)
+
+	var req models.CreateDelegationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+
+	delegationID, err := services.CreateDelegation(claims.EmployeeID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrDelegationOverlap):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrInvalidDelegation):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			log.Printf("Failed to create delegation for employee %s: %v", claims.EmployeeID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create delegation"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":      true,
+		"message":      "Delegation created successfully!",
+		"delegationId": delegationID,
+	})
+}
+
+func CancelDelegationHandler(c *gin.Context) {
+	claims, _ := middleware.GetAuthClaims(c)
+
+	delegationID, err := strconv.Atoi(c.Param("delegationId"))
+	if err != nil || delegationID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid delegation ID"})
+		return
+	}
+
+	if err := services.CancelDelegation(claims.EmployeeID, delegationID); err != nil {
+		if errors.Is(err, services.ErrDelegationNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Failed to cancel delegation %d: %v", delegationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel delegation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Delegation cancelled successfully!",
+	})
+}
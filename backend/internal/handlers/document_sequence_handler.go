@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"mantest/backend/internal/models"
+	"mantest/backend/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetDocumentSequencesHandler(c *gin.Context) {
+	sequences, err := services.GetDocumentSequences()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch document sequences"})
+		return
+	}
+	c.JSON(http.StatusOK, sequences)
+}
+
+func SaveDocumentSequenceHandler(c *gin.Context) {
+	var input models.DocumentSequenceInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+
+	if err := services.SaveDocumentSequence(c.Param("docType"), &input); err != nil {
+		if errors.Is(err, services.ErrInvalidSequence) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Failed to save document sequence %s: %v", c.Param("docType"), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save document sequence"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Document sequence saved successfully!",
+	})
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"mantest/backend/internal/middleware"
+	"mantest/backend/internal/models"
+	"mantest/backend/internal/services"
+	"mantest/backend/internal/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func parseDraftID(c *gin.Context) (int, bool) {
+	draftID, err := strconv.Atoi(c.Param("draftId"))
+	if err != nil || draftID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid draft ID"})
+		return 0, false
+	}
+	return draftID, true
+}
+
+// buildDraftRecord is buildRequestRecord without the required-field checks:
+// anything left empty is stored as not yet filled in. Values that are given
+// must still be valid.
+func buildDraftRecord(c *gin.Context, req *ManpowerRequest, bindErrs []models.FieldError) (*models.ManpowerRequestRecord, bool) {
+	return resolveRecord(c, req, bindErrs, false)
+}
+
+func respondDraftError(c *gin.Context, draftID int, err error) {
+	var incomplete *services.DraftIncompleteError
+	switch {
+	case errors.As(err, &incomplete):
+		fields := make([]models.FieldError, 0, len(incomplete.Missing))
+		for _, field := range incomplete.Missing {
+			fields = append(fields, validation.NewFieldError(field, validation.CodeRequired, ""))
+		}
+		c.JSON(http.StatusBadRequest, validation.Failure(fields))
+	case errors.Is(err, services.ErrDraftNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrDuplicateDocNumber):
+		c.JSON(http.StatusConflict, gin.H{"error": "Document number already exists. Please try again."})
+	default:
+		log.Printf("Draft %d: %v", draftID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process draft"})
+	}
+}
+
+func GetDraftsHandler(c *gin.Context) {
+	claims, _ := middleware.GetAuthClaims(c)
+
+	drafts, err := services.GetDrafts(claims.EmployeeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch drafts"})
+		return
+	}
+	c.JSON(http.StatusOK, drafts)
+}
+
+func GetDraftHandler(c *gin.Context) {
+	draftID, ok := parseDraftID(c)
+	if !ok {
+		return
+	}
+	claims, _ := middleware.GetAuthClaims(c)
+
+	draft, err := services.GetDraft(draftID, claims.EmployeeID)
+	if err != nil {
+		respondDraftError(c, draftID, err)
+		return
+	}
+	c.JSON(http.StatusOK, draft)
+}
+
+func saveDraft(c *gin.Context, draftID int) {
+	var req ManpowerRequest
+	bindErrs, ok := bindManpowerRequest(c, &req)
+	if !ok {
+		return
+	}
+	claims, _ := middleware.GetAuthClaims(c)
+
+	record, ok := buildDraftRecord(c, &req, bindErrs)
+	if !ok {
+		return
+	}
+	record.EmployeeID = claims.EmployeeID
+	record.CreatedBy = claims.EmployeeID
+
+	savedID, err := services.SaveDraft(draftID, record)
+	if err != nil {
+		respondDraftError(c, draftID, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Draft saved successfully!",
+		"draftId": savedID,
+	})
+}
+
+func CreateDraftHandler(c *gin.Context) {
+	saveDraft(c, 0)
+}
+
+func UpdateDraftHandler(c *gin.Context) {
+	draftID, ok := parseDraftID(c)
+	if !ok {
+		return
+	}
+	saveDraft(c, draftID)
+}
+
+func SubmitDraftHandler(c *gin.Context) {
+	draftID, ok := parseDraftID(c)
+	if !ok {
+		return
+	}
+	claims, _ := middleware.GetAuthClaims(c)
+
+	if !ensureActiveEmployee(c, claims.EmployeeID) {
+		return
+	}
+
+	if err := services.SubmitDraft(draftID, claims.EmployeeID); err != nil {
+		respondDraftError(c, draftID, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Manpower request received and saved successfully!",
+		"id":      draftID,
+	})
+}
+
+func DeleteDraftHandler(c *gin.Context) {
+	draftID, ok := parseDraftID(c)
+	if !ok {
+		return
+	}
+	claims, _ := middleware.GetAuthClaims(c)
+
+	if err := services.DiscardDraft(draftID, claims.EmployeeID); err != nil {
+		respondDraftError(c, draftID, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Draft discarded successfully!",
+	})
+}
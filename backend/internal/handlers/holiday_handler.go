@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"mantest/backend/internal/models"
+	"mantest/backend/internal/services"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxICSUploadSize = 1 << 20
+
+func parseHolidayID(c *gin.Context) (int, bool) {
+	holidayID, err := strconv.Atoi(c.Param("holidayId"))
+	if err != nil || holidayID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid holiday ID"})
+		return 0, false
+	}
+	return holidayID, true
+}
+
+func respondHolidayError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrHolidayNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrHolidayDateTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidHolidayDate), errors.Is(err, services.ErrInvalidCalendarFile):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("Holiday update failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update holidays"})
+	}
+}
+
+func GetHolidaysHandler(c *gin.Context) {
+	year := time.Now().Year()
+	if value := c.Query("year"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1900 || parsed > 9999 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year"})
+			return
+		}
+		year = parsed
+	}
+
+	holidays, err := services.GetHolidays(year)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch holidays"})
+		return
+	}
+	c.JSON(http.StatusOK, holidays)
+}
+
+func CreateHolidayHandler(c *gin.Context) {
+	var input models.HolidayInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+
+	holidayID, err := services.CreateHoliday(&input)
+	if err != nil {
+		respondHolidayError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":   true,
+		"message":   "Holiday created successfully!",
+		"holidayId": holidayID,
+	})
+}
+
+func UpdateHolidayHandler(c *gin.Context) {
+	holidayID, ok := parseHolidayID(c)
+	if !ok {
+		return
+	}
+
+	var input models.HolidayInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+
+	if err := services.UpdateHoliday(holidayID, &input); err != nil {
+		respondHolidayError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Holiday updated successfully!",
+	})
+}
+
+func DeleteHolidayHandler(c *gin.Context) {
+	holidayID, ok := parseHolidayID(c)
+	if !ok {
+		return
+	}
+
+	if err := services.DeleteHoliday(holidayID); err != nil {
+		respondHolidayError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Holiday deleted successfully!",
+	})
+}
+
+// ImportHolidaysHandler accepts an .ics file either as the "file" field of a
+// multipart form or as the raw request body.
+func ImportHolidaysHandler(c *gin.Context) {
+	var reader io.Reader
+	if file, err := c.FormFile("file"); err == nil {
+		f, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file"})
+			return
+		}
+		defer f.Close()
+		reader = f
+	} else {
+		reader = c.Request.Body
+	}
+
+	data, err := io.ReadAll(io.LimitReader(reader, maxICSUploadSize+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read calendar data"})
+		return
+	}
+	if len(data) > maxICSUploadSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Calendar file is too large"})
+		return
+	}
+
+	result, err := services.ImportHolidaysICS(data)
+	if err != nil {
+		respondHolidayError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
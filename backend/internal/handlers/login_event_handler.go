@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"mantest/backend/internal/models"
+	"mantest/backend/internal/services"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultLoginEventLimit = 50
+	maxLoginEventLimit     = 500
+)
+
+func parseTimeQuery(c *gin.Context, param string) (*time.Time, bool) {
+	value := c.Query(param)
+	if value == "" {
+		return nil, true
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid value for " + param + ": expected RFC3339 timestamp"})
+		return nil, false
+	}
+	return &parsed, true
+}
+
+func GetLoginEventsHandler(c *gin.Context) {
+	filter := models.LoginEventFilter{
+		Email:      c.Query("email"),
+		EmployeeID: c.Query("employeeId"),
+		IPAddress:  c.Query("ip"),
+		Limit:      defaultLoginEventLimit,
+	}
+
+	if value := c.Query("success"); value != "" {
+		success, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid value for success: expected true or false"})
+			return
+		}
+		filter.Success = &success
+	}
+
+	var ok bool
+	if filter.From, ok = parseTimeQuery(c, "from"); !ok {
+		return
+	}
+	if filter.To, ok = parseTimeQuery(c, "to"); !ok {
+		return
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid value for limit"})
+			return
+		}
+		if limit > maxLoginEventLimit {
+			limit = maxLoginEventLimit
+		}
+		filter.Limit = limit
+	}
+
+	if value := c.Query("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid value for offset"})
+			return
+		}
+		filter.Offset = offset
+	}
+
+	events, err := services.GetLoginEvents(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch login events"})
+		return
+	}
+	c.JSON(http.StatusOK, events)
+}
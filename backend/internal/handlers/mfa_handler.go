@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"mantest/backend/internal/middleware"
+	"mantest/backend/internal/models"
+	"mantest/backend/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func respondMFAError(c *gin.Context, err error) {
+	var throttled *services.LoginThrottledError
+	switch {
+	case errors.As(err, &throttled):
+		c.JSON(http.StatusTooManyRequests, gin.H{"message": "Too many failed login attempts. Please try again later."})
+	case errors.Is(err, services.ErrTokenExpired), errors.Is(err, services.ErrTokenInvalid):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized", "message": "Challenge token is invalid or has expired"})
+	case errors.Is(err, services.ErrMFAInvalidCode):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrMFAAlreadyEnabled), errors.Is(err, services.ErrMFANotEnrolled):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrMFARequired):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrEmployeeNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process two-factor request"})
+	}
+}
+
+func VerifyMFALoginHandler(c *gin.Context) {
+	var req models.MFAChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	response, err := services.CompleteMFALogin(req.ChallengeToken, req.Code, clientInfo(c))
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func BeginLoginMFAEnrollmentHandler(c *gin.Context) {
+	var req models.MFAChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	enrollment, err := services.BeginChallengeEnrollment(req.ChallengeToken)
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, enrollment)
+}
+
+func ConfirmLoginMFAEnrollmentHandler(c *gin.Context) {
+	var req models.MFAChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	response, err := services.ConfirmChallengeEnrollment(req.ChallengeToken, req.Code, clientInfo(c))
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func GetMFAStatusHandler(c *gin.Context) {
+	claims, _ := middleware.GetAuthClaims(c)
+
+	status, err := services.GetMFAStatus(claims.EmployeeID)
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+func BeginMFAEnrollmentHandler(c *gin.Context) {
+	claims, _ := middleware.GetAuthClaims(c)
+
+	enrollment, err := services.BeginMFAEnrollment(claims.EmployeeID, claims.Email)
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, enrollment)
+}
+
+func ConfirmMFAEnrollmentHandler(c *gin.Context) {
+	claims, _ := middleware.GetAuthClaims(c)
+
+	var req models.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	codes, err := services.ConfirmMFAEnrollment(claims.EmployeeID, req.Code)
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.MFARecoveryCodes{RecoveryCodes: codes})
+}
+
+func DisableMFAHandler(c *gin.Context) {
+	claims, _ := middleware.GetAuthClaims(c)
+
+	var req models.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	if err := services.DisableMFA(claims.EmployeeID, req.Code); err != nil {
+		respondMFAError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Two-factor authentication disabled.",
+	})
+}
+
+func RegenerateRecoveryCodesHandler(c *gin.Context) {
+	claims, _ := middleware.GetAuthClaims(c)
+
+	var req models.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	codes, err := services.RegenerateRecoveryCodes(claims.EmployeeID, req.Code)
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.MFARecoveryCodes{RecoveryCodes: codes})
+}
+
+func ResetEmployeeMFAHandler(c *gin.Context) {
+	if err := services.ResetEmployeeMFA(c.Param("employeeId")); err != nil {
+		if errors.Is(err, services.ErrEmployeeNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+			return
+		}
+		log.Printf("Failed to reset MFA for employee %s: %v", c.Param("employeeId"), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset two-factor authentication"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Two-factor authentication reset successfully!",
+	})
+}
+
+func SetRoleMFAHandler(c *gin.Context) {
+	var req models.SetRoleMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+
+	if err := services.SetRoleMFARequired(c.Param("roleName"), *req.Required); err != nil {
+		if errors.Is(err, services.ErrRoleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Failed to set MFA policy for role %s: %v", c.Param("roleName"), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update role MFA policy"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Role MFA policy updated successfully!",
+	})
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"mantest/backend/internal/middleware"
+	"mantest/backend/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetNotificationsHandler(c *gin.Context) {
+	claims, _ := middleware.GetAuthClaims(c)
+
+	unreadOnly := c.Query("unread") == "true"
+	notifications, err := services.GetNotifications(claims.EmployeeID, unreadOnly)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+		return
+	}
+	c.JSON(http.StatusOK, notifications)
+}
+
+func MarkNotificationReadHandler(c *gin.Context) {
+	claims, _ := middleware.GetAuthClaims(c)
+
+	notificationID, err := strconv.Atoi(c.Param("notificationId"))
+	if err != nil || notificationID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	if err := services.MarkNotificationRead(claims.EmployeeID, notificationID); err != nil {
+		if errors.Is(err, services.ErrNotificationNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Failed to mark notification %d as read: %v", notificationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"mantest/backend/internal/middleware"
+	"mantest/backend/internal/models"
+	"mantest/backend/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ForcePasswordResetHandler(c *gin.Context) {
+	claims, _ := middleware.GetAuthClaims(c)
+
+	issued, err := services.ForcePasswordReset(c.Param("employeeId"), claims.EmployeeID)
+	if err != nil {
+		if errors.Is(err, services.ErrEmployeeNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Active employee not found"})
+			return
+		}
+		log.Printf("Failed to force password reset for %s: %v", c.Param("employeeId"), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue password reset"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, issued)
+}
+
+func ResetPasswordHandler(c *gin.Context) {
+	var req models.PasswordResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+
+	if err := services.RedeemPasswordReset(req.Token, req.NewPassword); err != nil {
+		if errors.Is(err, services.ErrInvalidResetToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Failed to redeem password reset: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Password has been reset successfully!",
+	})
+}
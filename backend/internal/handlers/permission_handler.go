@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"mantest/backend/internal/models"
+	"mantest/backend/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetRolePermissionsHandler(c *gin.Context) {
+	roles, err := services.GetAllRolePermissions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch role permissions"})
+		return
+	}
+	c.JSON(http.StatusOK, roles)
+}
+
+func SetRolePermissionsHandler(c *gin.Context) {
+	var req models.SetRolePermissionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+
+	if err := services.SetRolePermissions(c.Param("roleName"), req.Permissions); err != nil {
+		if errors.Is(err, services.ErrInvalidRolePermissions) || errors.Is(err, services.ErrAdminLockout) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Failed to update permissions of role %s: %v", c.Param("roleName"), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update role permissions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Role permissions updated successfully!",
+	})
+}
@@ -1,145 +1,340 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"log"
-	"mantest/backend/internal/database"
+	"mantest/backend/internal/middleware"
+	"mantest/backend/internal/models"
 	"mantest/backend/internal/services"
+	"mantest/backend/internal/validation"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 )
 
 type ManpowerRequest struct {
-	DocumentDate          string `json:"documentDate"`
-	Department            string `json:"department"` 
-	Section               string `json:"section"`    
-	EmploymentType        string `json:"employmentType"` 
-	ContractType          string `json:"contractType"`   
-	RequestReason         string `json:"requestReason"`  
-	RequesterName         string `json:"requesterName"`
-	PositionId            string `json:"positionId"`     
-	PositionRequire       string `json:"positionRequire"` 
-	AgeFrom               string `json:"ageFrom"`
-	AgeTo                 string `json:"ageTo"`
-	Gender                string `json:"gender"`     
-	Nationality           string `json:"nationality"` 
-	Experience            string `json:"experience"`  
-	EducationLevel        string `json:"educationLevel"` 
-	SpecialQualifications string `json:"specialQualifications"`
+	DocumentDate          string              `json:"documentDate"`
+	Department            string              `json:"department"`
+	Section               string              `json:"section"`
+	EmploymentType        string              `json:"employmentType"`
+	ContractType          string              `json:"contractType"`
+	RequestReason         string              `json:"requestReason"`
+	RequesterName         string              `json:"requesterName" binding:"max=200"`
+	PositionId            string              `json:"positionId" binding:"max=50"`
+	PositionRequire       string              `json:"positionRequire" binding:"max=100"`
+	AgeFrom               validation.IntInput `json:"ageFrom"`
+	AgeTo                 validation.IntInput `json:"ageTo"`
+	Gender                string              `json:"gender"`
+	Nationality           string              `json:"nationality"`
+	Experience            string              `json:"experience"`
+	EducationLevel        string              `json:"educationLevel"`
+	SpecialQualifications string              `json:"specialQualifications" binding:"max=2000"`
+	RequesterEmployeeID   string              `json:"requesterEmployeeId" binding:"max=50"`
+	Headcount             int                 `json:"headcount" binding:"min=0,max=999"`
+	Lines                 []RequestLine       `json:"lines" binding:"max=50,dive"`
+
+	// Master-data IDs. Each takes precedence over the matching display name
+	// above, which is still accepted from older clients.
+	DepartmentID      int `json:"departmentId"`
+	EmploymentTypeID  int `json:"employmentTypeId"`
+	ContractTypeID    int `json:"contractTypeId"`
+	RequestReasonID   int `json:"requestReasonId"`
+	PositionRequireID int `json:"positionRequireId"`
+	GenderID          int `json:"genderId"`
+	NationalityID     int `json:"nationalityId"`
+	ExperienceID      int `json:"experienceId"`
+	EducationLevelID  int `json:"educationLevelId"`
+}
+
+// RequestLine is one position asked for on the form. Clients that send no
+// lines give a single position on the request itself instead. Quantity may
+// be left out (0) on a draft only.
+type RequestLine struct {
+	PositionRequireID int    `json:"positionRequireId"`
+	PositionRequire   string `json:"positionRequire" binding:"max=100"`
+	PositionId        string `json:"positionId" binding:"max=50"`
+	Quantity          int    `json:"quantity" binding:"min=0,max=999"`
+	Qualifications    string `json:"qualifications" binding:"max=2000"`
+	TargetHireDate    string `json:"targetHireDate"`
 }
 
 func parseDate(dateStr string) (time.Time, error) {
 	return time.Parse("02/01/2006", dateStr)
 }
 
-func lookupName(c *gin.Context, tableName, name string) (int, error) {
-	id, err := services.GetIDByName(tableName, name)
+func ensureActiveEmployee(c *gin.Context, employeeID string) bool {
+	active, err := services.IsEmployeeActive(employeeID)
+	if err != nil {
+		if errors.Is(err, services.ErrEmployeeNotFound) {
+			c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("Employee %s is not a valid requester", employeeID)})
+			return false
+		}
+		log.Printf("Failed to verify status of employee %s: %v", employeeID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify requester status"})
+		return false
+	}
+	if !active {
+		c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("Employee %s is not active", employeeID)})
+		return false
+	}
+	return true
+}
+
+func resolveRequesterID(c *gin.Context, requestedID string) (string, bool) {
+	claims, ok := middleware.GetAuthClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized", "message": "Authentication required"})
+		return "", false
+	}
+
+	if !ensureActiveEmployee(c, claims.EmployeeID) {
+		return "", false
+	}
+
+	if requestedID == "" || requestedID == claims.EmployeeID {
+		return claims.EmployeeID, true
+	}
+
+	if !middleware.HasPermission(c, models.PermRequestsCreateOnBehalf) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to submit requests on behalf of another employee"})
+		return "", false
+	}
+
+	if !ensureActiveEmployee(c, requestedID) {
+		return "", false
+	}
+	return requestedID, true
+}
+
+// Accepted applicant ages.
+const (
+	minApplicantAge = 15
+	maxApplicantAge = 70
+)
+
+// bindManpowerRequest decodes the payload and applies its binding rules. Rule
+// failures are returned so they can be reported together with the checks in
+// resolveRequestFields; a payload that cannot be decoded at all is answered
+// here.
+func bindManpowerRequest(c *gin.Context, req *ManpowerRequest) ([]models.FieldError, bool) {
+	err := c.ShouldBindJSON(req)
+	if err == nil {
+		return nil, true
+	}
+	var ruleErrs validator.ValidationErrors
+	if errors.As(err, &ruleErrs) {
+		return validation.FromBindError(err), true
+	}
+	respondFieldErrors(c, validation.FromBindError(err))
+	return nil, false
+}
+
+// resolveRequestFields turns the form payload into a record, collecting every
+// invalid field rather than stopping at the first. With required set an empty
+// field is an error too; drafts leave such fields unset.
+func resolveRequestFields(req *ManpowerRequest, required bool) (*models.ManpowerRequestRecord, []models.FieldError, error) {
+	record := &models.ManpowerRequestRecord{
+		SpecialQualifications: req.SpecialQualifications,
+	}
+
+	var v validation.Validator
+
+	lines := req.Lines
+	linePrefix := func(i int) string { return fmt.Sprintf("lines[%d].", i) }
+	if len(lines) == 0 {
+		// Older clients may leave the headcount out; it means one position.
+		quantity := req.Headcount
+		if quantity <= 0 {
+			quantity = 1
+		}
+		lines = []RequestLine{{
+			PositionRequireID: req.PositionRequireID,
+			PositionRequire:   req.PositionRequire,
+			PositionId:        req.PositionId,
+			Quantity:          quantity,
+		}}
+		linePrefix = func(int) string { return "" }
+	} else {
+		// The lines replace the single position on the request itself, so
+		// sending both leaves it unclear which one was meant.
+		header := []struct {
+			field string
+			set   bool
+		}{
+			{"positionRequireId", req.PositionRequireID != 0},
+			{"positionRequire", req.PositionRequire != ""},
+			{"positionId", req.PositionId != ""},
+			{"headcount", req.Headcount != 0},
+		}
+		for _, h := range header {
+			if h.set {
+				v.Add(h.field, validation.CodeNotWithLines, "")
+			}
+		}
+	}
+
+	lookups := []services.MasterDataLookup{
+		{Field: "department", Table: "department", ID: req.DepartmentID, Name: req.Department},
+		{Field: "employmentType", Table: "employment_type", ID: req.EmploymentTypeID, Name: req.EmploymentType},
+		{Field: "contractType", Table: "contract_type", ID: req.ContractTypeID, Name: req.ContractType},
+		{Field: "requestReason", Table: "request_reason", ID: req.RequestReasonID, Name: req.RequestReason},
+		{Field: "gender", Table: "gender", ID: req.GenderID, Name: req.Gender},
+		{Field: "nationality", Table: "nationality", ID: req.NationalityID, Name: req.Nationality},
+		{Field: "experience", Table: "experience", ID: req.ExperienceID, Name: req.Experience},
+		{Field: "educationLevel", Table: "education_level", ID: req.EducationLevelID, Name: req.EducationLevel},
+	}
+	headerLookups := len(lookups)
+	for i, line := range lines {
+		lookups = append(lookups, services.MasterDataLookup{
+			Field: linePrefix(i) + "positionRequire", Table: "position", ID: line.PositionRequireID, Name: line.PositionRequire,
+		})
+	}
+	items, invalid, err := services.ResolveMasterData(lookups)
+	if err != nil {
+		return nil, nil, err
+	}
+	v.Merge(invalid)
+
+	dests := []*int{
+		&record.DeptID, &record.EmploymentTypeID, &record.ContractTypeID, &record.ReasonID,
+		&record.GenderID, &record.NationalityID, &record.ExperienceID, &record.EducationLevelID,
+	}
+	for i, dest := range dests {
+		*dest = items[i].ID
+	}
+	if required {
+		for _, l := range lookups {
+			if l.ID == 0 {
+				v.Required(l.Field, l.Name)
+			}
+		}
+	}
+
+	if strings.TrimSpace(req.DocumentDate) != "" {
+		if docDate, ok := v.Date("documentDate", req.DocumentDate, "02/01/2006", "DD/MM/YYYY"); ok {
+			v.NotAfter("documentDate", docDate, services.BusinessNow())
+			record.DocDate = docDate
+		}
+	} else if required {
+		v.Required("documentDate", "")
+	}
+
+	ageFromOK, ageToOK := false, false
+	if !req.AgeFrom.IsEmpty() {
+		record.MinAge, ageFromOK = v.IntRange("ageFrom", req.AgeFrom, minApplicantAge, maxApplicantAge)
+	} else if required {
+		v.Required("ageFrom", "")
+	}
+	if !req.AgeTo.IsEmpty() {
+		record.MaxAge, ageToOK = v.IntRange("ageTo", req.AgeTo, minApplicantAge, maxApplicantAge)
+	} else if required {
+		v.Required("ageTo", "")
+	}
+	if ageFromOK && ageToOK {
+		v.Ordered("ageFrom", record.MinAge, "ageTo", record.MaxAge)
+	}
+
+	record.Lines = make([]models.ManpowerRequestLineRecord, len(lines))
+	for i, line := range lines {
+		item := items[headerLookups+i]
+		rec := &record.Lines[i]
+		rec.PosID = item.ID
+		rec.PositionCode = line.PositionId
+		rec.PositionName = line.PositionRequire
+		// The stored position name follows the resolved entry, so a line
+		// sent by ID still carries it.
+		if item.Name != "" {
+			rec.PositionName = item.Name
+		}
+		rec.Qualifications = line.Qualifications
+		rec.Quantity = line.Quantity
+		if rec.Quantity == 0 && required {
+			v.Required(linePrefix(i)+"quantity", "")
+		}
+		record.Headcount += rec.Quantity
+
+		if strings.TrimSpace(line.TargetHireDate) == "" {
+			continue
+		}
+		field := linePrefix(i) + "targetHireDate"
+		if date, ok := v.Date(field, line.TargetHireDate, "02/01/2006", "DD/MM/YYYY"); ok {
+			if !record.DocDate.IsZero() {
+				v.NotBefore(field, date, record.DocDate)
+			}
+			if !services.IsBusinessDay(date) {
+				v.Add(field, validation.CodeNotWorkingDay, line.TargetHireDate)
+			}
+			rec.TargetHireDate = date
+		}
+	}
+	// The request itself carries the first line's position, which lists show
+	// and the submission checks rely on.
+	first := record.Lines[0]
+	record.PosID = first.PosID
+	record.RequiredPositionCode = first.PositionCode
+	record.RequiredPositionName = first.PositionName
+
+	return record, v.Errors(), nil
+}
+
+func respondFieldErrors(c *gin.Context, invalid []models.FieldError) {
+	c.JSON(http.StatusBadRequest, validation.Failure(invalid))
+}
+
+// resolveRecord runs resolveRequestFields and answers the request itself when
+// the payload cannot be used.
+func resolveRecord(c *gin.Context, req *ManpowerRequest, bindErrs []models.FieldError, required bool) (*models.ManpowerRequestRecord, bool) {
+	record, invalid, err := resolveRequestFields(req, required)
 	if err != nil {
-		log.Printf("Lookup Error: Failed to find ID for %s '%s': %v", tableName, name, err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid selection for %s: %s", tableName, name)})
-		return 0, err
+		log.Printf("Failed to resolve request fields: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate request"})
+		return nil, false
 	}
-	return id, nil
+	invalid = append(bindErrs, invalid...)
+	if len(invalid) > 0 {
+		respondFieldErrors(c, invalid)
+		return nil, false
+	}
+	return record, true
+}
+
+func buildRequestRecord(c *gin.Context, req *ManpowerRequest, bindErrs []models.FieldError) (*models.ManpowerRequestRecord, bool) {
+	return resolveRecord(c, req, bindErrs, true)
 }
 
 func CreateManpowerRequestHandler(c *gin.Context) {
 	var req ManpowerRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+	bindErrs, ok := bindManpowerRequest(c, &req)
+	if !ok {
 		return
 	}
 
-	employeeID := "E003" 
-	docNumber := time.Now().Format("20060102-1") 
-	
-	deptID, err := lookupName(c, "department", req.Department)
-	if err != nil { return }
-	
-	posID, err := lookupName(c, "position", req.PositionRequire)
-	if err != nil { return }
-	
-	etID, err := lookupName(c, "employment_type", req.EmploymentType)
-	if err != nil { return }
-	
-	ctID, err := lookupName(c, "contract_type", req.ContractType)
-	if err != nil { return }
-
-	rrID, err := lookupName(c, "request_reason", req.RequestReason)
-	if err != nil { return }
-	
-	genderID, err := lookupName(c, "gender", req.Gender)
-	if err != nil { return }
-
-	natID, err := lookupName(c, "nationality", req.Nationality)
-	if err != nil { return }
-
-	expID, err := lookupName(c, "experience", req.Experience)
-	if err != nil { return }
-
-	eduID, err := lookupName(c, "education_level", req.EducationLevel)
-	if err != nil { return }
-
-	docDate, err := parseDate(req.DocumentDate)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Expected DD/MM/YYYY."})
+	employeeID, ok := resolveRequesterID(c, req.RequesterEmployeeID)
+	if !ok {
 		return
 	}
-    
-    minAge, err := strconv.Atoi(req.AgeFrom)
-    if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid value for Age From: '%s'", req.AgeFrom)})
-        return
-    }
-
-    maxAge, err := strconv.Atoi(req.AgeTo)
-    if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid value for Age To: '%s'", req.AgeTo)})
-        return
-    }
-
-	query := `
-		INSERT INTO manpower_requests (
-			doc_number, employee_id, doc_date, requesting_dept_id, requesting_pos_id,
-			employment_type_id, contract_type_id, reason_id, 
-			required_position_code, required_position_name, min_age, max_age, 
-			gender_id, nationality_id, experience_id, education_level_id, 
-			special_qualifications
-		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
-		RETURNING request_id
-	`
-	
-	var newRequestID int
-	err = database.DB.QueryRow(query,
-		docNumber,
-		employeeID,
-		docDate,
-		deptID, 
-		posID, 
-		etID,   
-		ctID,   
-		rrID,   
-		req.PositionId, 
-		req.PositionRequire, 
-		minAge, 
-		maxAge, 
-		genderID, 
-		natID,    
-		expID,    
-		eduID,    
-		req.SpecialQualifications,
-	).Scan(&newRequestID)
+	claims, _ := middleware.GetAuthClaims(c)
 
+	record, ok := buildRequestRecord(c, &req, bindErrs)
+	if !ok {
+		return
+	}
+	record.EmployeeID = employeeID
+	record.CreatedBy = claims.EmployeeID
+
+	newRequestID, err := services.CreateManpowerRequest(record)
 	if err != nil {
-		log.Printf("SQL INSERT Error: %v", err)
-		if err.Error() == "pq: duplicate key value violates unique constraint \"manpower_requests_doc_number_key\"" {
+		if errors.Is(err, services.ErrDuplicateDocNumber) {
 			c.JSON(http.StatusConflict, gin.H{"error": "Document number already exists. Please try again."})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save manpower request to database."})
+		log.Printf("Failed to save manpower request: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save manpower request"})
 		return
 	}
 
@@ -148,4 +343,112 @@ func CreateManpowerRequestHandler(c *gin.Context) {
 		"message": "Manpower request received and saved successfully!",
 		"id":      newRequestID,
 	})
-}
\ No newline at end of file
+}
+
+const (
+	defaultRequestPageSize = 10
+	maxRequestPageSize     = 100
+)
+
+func parseDateQuery(c *gin.Context, param string) (*time.Time, bool) {
+	value := c.Query(param)
+	if value == "" {
+		return nil, true
+	}
+
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid value for " + param + ": expected YYYY-MM-DD"})
+		return nil, false
+	}
+	return &parsed, true
+}
+
+func parsePositiveIntQuery(c *gin.Context, param string, fallback int) (int, bool) {
+	value := c.Query(param)
+	if value == "" {
+		return fallback, true
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid value for " + param})
+		return 0, false
+	}
+	return parsed, true
+}
+
+// GetManpowerRequestsHandler lists submitted requests. Without
+// requests:read_all the caller only sees requests they raised or are on the
+// approval chain of.
+func GetManpowerRequestsHandler(c *gin.Context) {
+	claims, _ := middleware.GetAuthClaims(c)
+	filter := models.ManpowerRequestFilter{
+		LifecycleStatus: c.Query("status"),
+		RequesterID:     c.Query("requesterId"),
+		DocNumber:       c.Query("docNumber"),
+	}
+	if !middleware.HasPermission(c, models.PermRequestsReadAll) {
+		filter.ViewerID = claims.EmployeeID
+	}
+
+	if value := c.Query("awaitingMe"); value != "" {
+		awaiting, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid value for awaitingMe: expected true or false"})
+			return
+		}
+		if awaiting {
+			filter.AwaitingID = claims.EmployeeID
+		}
+	}
+
+	var ok bool
+	if filter.DepartmentID, ok = parsePositiveIntQuery(c, "departmentId", 0); !ok {
+		return
+	}
+	if filter.From, ok = parseDateQuery(c, "dateFrom"); !ok {
+		return
+	}
+	if filter.To, ok = parseDateQuery(c, "dateTo"); !ok {
+		return
+	}
+	if filter.Page, ok = parsePositiveIntQuery(c, "page", 1); !ok {
+		return
+	}
+	if filter.PageSize, ok = parsePositiveIntQuery(c, "pageSize", defaultRequestPageSize); !ok {
+		return
+	}
+	if filter.PageSize > maxRequestPageSize {
+		filter.PageSize = maxRequestPageSize
+	}
+
+	list, err := services.GetManpowerRequests(filter)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidStatusFilter) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch manpower requests"})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+func GetManpowerRequestHandler(c *gin.Context) {
+	requestID, ok := parseRequestID(c)
+	if !ok || !ensureCanViewRequest(c, requestID) {
+		return
+	}
+
+	detail, err := services.GetManpowerRequest(requestID)
+	if err != nil {
+		if errors.Is(err, services.ErrRequestNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch manpower request"})
+		return
+	}
+	c.JSON(http.StatusOK, detail)
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"mantest/backend/internal/middleware"
+	"mantest/backend/internal/models"
+	"mantest/backend/internal/services"
+	"mantest/backend/internal/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func respondLifecycleError(c *gin.Context, requestID int, err error) {
+	switch {
+	case errors.Is(err, services.ErrRequestNotFound), errors.Is(err, services.ErrRequestLineNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNotRequester):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidTransition), errors.Is(err, services.ErrLineOverfilled):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("Failed to update status of request %d: %v", requestID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update request"})
+	}
+}
+
+func WithdrawRequestHandler(c *gin.Context) {
+	requestID, ok := parseRequestID(c)
+	if !ok {
+		return
+	}
+
+	// The reason is optional, so an empty body is a withdrawal without one.
+	var req models.WithdrawRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+
+	claims, _ := middleware.GetAuthClaims(c)
+	if err := services.WithdrawRequest(requestID, claims.EmployeeID, req.Reason); err != nil {
+		respondLifecycleError(c, requestID, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Request withdrawn successfully!",
+	})
+}
+
+func ResubmitRequestHandler(c *gin.Context) {
+	requestID, ok := parseRequestID(c)
+	if !ok {
+		return
+	}
+
+	var req ManpowerRequest
+	bindErrs, ok := bindManpowerRequest(c, &req)
+	if !ok {
+		return
+	}
+
+	claims, _ := middleware.GetAuthClaims(c)
+	record, ok := buildRequestRecord(c, &req, bindErrs)
+	if !ok {
+		return
+	}
+	record.CreatedBy = claims.EmployeeID
+
+	if err := services.ResubmitManpowerRequest(requestID, record); err != nil {
+		respondLifecycleError(c, requestID, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Request resubmitted successfully!",
+	})
+}
+
+func FillRequestHandler(c *gin.Context) {
+	requestID, ok := parseRequestID(c)
+	if !ok {
+		return
+	}
+
+	// Notes are optional, so an empty body fills the request without any.
+	var req models.FillRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+
+	claims, _ := middleware.GetAuthClaims(c)
+	if err := services.MarkRequestFilled(requestID, claims.EmployeeID, req.Notes); err != nil {
+		respondLifecycleError(c, requestID, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Request marked as filled!",
+	})
+}
+
+// FillRequestLineHandler records hires against one line of an approved
+// request. The request closes as filled with the last open position.
+func FillRequestLineHandler(c *gin.Context) {
+	requestID, ok := parseRequestID(c)
+	if !ok {
+		return
+	}
+	lineID, err := strconv.Atoi(c.Param("lineId"))
+	if err != nil || lineID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid line ID"})
+		return
+	}
+
+	var req models.FillLineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondFieldErrors(c, validation.FromBindError(err))
+		return
+	}
+
+	claims, _ := middleware.GetAuthClaims(c)
+	completed, err := services.FillRequestLine(requestID, lineID, claims.EmployeeID, req.Quantity, req.Notes)
+	if err != nil {
+		respondLifecycleError(c, requestID, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":       true,
+		"message":       "Request line updated successfully!",
+		"requestFilled": completed,
+	})
+}
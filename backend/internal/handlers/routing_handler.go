@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"mantest/backend/internal/models"
+	"mantest/backend/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func parseRuleID(c *gin.Context) (int, bool) {
+	ruleID, err := strconv.Atoi(c.Param("ruleId"))
+	if err != nil || ruleID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule ID"})
+		return 0, false
+	}
+	return ruleID, true
+}
+
+func GetRoutingRulesHandler(c *gin.Context) {
+	rules, err := services.GetRoutingRules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch routing rules"})
+		return
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+func CreateRoutingRuleHandler(c *gin.Context) {
+	var input models.RoutingRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+
+	ruleID, err := services.CreateRoutingRule(&input)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidRoutingRule) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Failed to create routing rule: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save routing rule"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Routing rule created successfully!",
+		"ruleId":  ruleID,
+	})
+}
+
+func UpdateRoutingRuleHandler(c *gin.Context) {
+	ruleID, ok := parseRuleID(c)
+	if !ok {
+		return
+	}
+
+	var input models.RoutingRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+
+	if err := services.UpdateRoutingRule(ruleID, &input); err != nil {
+		switch {
+		case errors.Is(err, services.ErrRoutingRuleNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrInvalidRoutingRule):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			log.Printf("Failed to update routing rule %d: %v", ruleID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update routing rule"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Routing rule updated successfully!",
+	})
+}
+
+func DeleteRoutingRuleHandler(c *gin.Context) {
+	ruleID, ok := parseRuleID(c)
+	if !ok {
+		return
+	}
+
+	if err := services.DeactivateRoutingRule(ruleID); err != nil {
+		if errors.Is(err, services.ErrRoutingRuleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Routing rule deactivated successfully!",
+	})
+}
+
+func DryRunRoutingHandler(c *gin.Context) {
+	var input models.RoutingDryRunRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+
+	result, err := services.DryRunRouting(&input)
+	if err != nil {
+		log.Printf("Routing dry-run failed: %v", err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
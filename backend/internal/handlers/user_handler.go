@@ -1,24 +1,26 @@
 package handlers
 
 import (
+	"errors"
+	"mantest/backend/internal/middleware"
 	"mantest/backend/internal/services"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
-func GetUserProfileHandler(c *gin.Context) {
-	userEmail := c.Query("email")
-	if userEmail == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Email query parameter is required"})
-		return
-	}
+func GetCurrentUserHandler(c *gin.Context) {
+	claims, _ := middleware.GetAuthClaims(c)
 
-	profile, err := services.GetUserProfileByEmail(userEmail)
+	user, err := services.GetCurrentUser(claims.EmployeeID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		if errors.Is(err, services.ErrEmployeeNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user profile"})
 		return
 	}
 
-	c.JSON(http.StatusOK, profile)
-}
\ No newline at end of file
+	c.JSON(http.StatusOK, user)
+}
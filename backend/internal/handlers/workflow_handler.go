@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"mantest/backend/internal/middleware"
+	"mantest/backend/internal/models"
+	"mantest/backend/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func parseRequestID(c *gin.Context) (int, bool) {
+	requestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || requestID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request ID"})
+		return 0, false
+	}
+	return requestID, true
+}
+
+func ensureCanViewRequest(c *gin.Context, requestID int) bool {
+	if middleware.HasPermission(c, models.PermRequestsReadAll) {
+		return true
+	}
+
+	claims, _ := middleware.GetAuthClaims(c)
+	allowed, err := services.CanViewRequest(requestID, claims.EmployeeID)
+	if err != nil {
+		log.Printf("Failed to check access to request %d: %v", requestID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify access to request"})
+		return false
+	}
+	if !allowed {
+		c.JSON(http.StatusNotFound, gin.H{"error": services.ErrRequestNotFound.Error()})
+		return false
+	}
+	return true
+}
+
+func GetRequestWorkflowHandler(c *gin.Context) {
+	requestID, ok := parseRequestID(c)
+	if !ok || !ensureCanViewRequest(c, requestID) {
+		return
+	}
+
+	state, err := services.GetWorkflowState(requestID)
+	if err != nil {
+		if errors.Is(err, services.ErrRequestNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch approval workflow"})
+		return
+	}
+	c.JSON(http.StatusOK, state)
+}
+
+func CreateDecisionHandler(c *gin.Context) {
+	requestID, ok := parseRequestID(c)
+	if !ok {
+		return
+	}
+
+	var req models.DecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+
+	claims, _ := middleware.GetAuthClaims(c)
+	if err := services.DecideRequest(requestID, claims.EmployeeID, req.Decision, req.Notes); err != nil {
+		switch {
+		case errors.Is(err, services.ErrRequestNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrNotStepApprover), errors.Is(err, services.ErrOwnRequest):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrAlreadyDecided):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrStepNotPending):
+			c.JSON(http.StatusConflict, gin.H{"error": "This request has no step awaiting a decision"})
+		case errors.Is(err, services.ErrNotesRequired):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			log.Printf("Failed to record decision on request %d: %v", requestID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record decision"})
+		}
+		return
+	}
+
+	state, err := services.GetWorkflowState(requestID)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{"success": true, "message": "Decision recorded successfully!"})
+		return
+	}
+	c.JSON(http.StatusOK, state)
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"errors"
+	"mantest/backend/internal/models"
+	"mantest/backend/internal/services"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ContextClaimsKey     = "authClaims"
+	ContextEmployeeIDKey = "employeeID"
+	ContextRoleKey       = "roleName"
+)
+
+func abortUnauthorized(c *gin.Context, message string) {
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+		"error":   "unauthorized",
+		"message": message,
+	})
+}
+
+func AuthRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if header == "" {
+			abortUnauthorized(c, "Missing Authorization header")
+			return
+		}
+
+		scheme, tokenString, found := strings.Cut(header, " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(tokenString) == "" {
+			abortUnauthorized(c, "Authorization header must be in the form 'Bearer <token>'")
+			return
+		}
+
+		claims, err := services.ParseToken(strings.TrimSpace(tokenString))
+		if err != nil {
+			if errors.Is(err, services.ErrTokenExpired) {
+				abortUnauthorized(c, "Token has expired")
+				return
+			}
+			abortUnauthorized(c, "Invalid token")
+			return
+		}
+
+		if err := services.ValidateSession(claims.SessionID, claims.EmployeeID); err != nil {
+			if errors.Is(err, services.ErrSessionRevoked) {
+				abortUnauthorized(c, "Session has been revoked or has expired")
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify session"})
+			return
+		}
+
+		c.Set(ContextClaimsKey, claims)
+		c.Set(ContextEmployeeIDKey, claims.EmployeeID)
+		c.Set(ContextRoleKey, claims.RoleName)
+		c.Next()
+	}
+}
+
+func GetAuthClaims(c *gin.Context) (*models.AuthClaims, bool) {
+	value, exists := c.Get(ContextClaimsKey)
+	if !exists {
+		return nil, false
+	}
+	claims, ok := value.(*models.AuthClaims)
+	return claims, ok
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"log"
+	"mantest/backend/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := GetAuthClaims(c)
+		if !ok {
+			abortUnauthorized(c, "Authentication required")
+			return
+		}
+
+		for _, permission := range permissions {
+			allowed, err := services.RoleHasPermission(claims.RoleName, permission)
+			if err != nil {
+				log.Printf("Permission check failed for role %s: %v", claims.RoleName, err)
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify permissions"})
+				return
+			}
+			if !allowed {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+					"error":      "forbidden",
+					"message":    "You do not have permission to perform this action",
+					"permission": permission,
+				})
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+func HasPermission(c *gin.Context, permission string) bool {
+	claims, ok := GetAuthClaims(c)
+	if !ok {
+		return false
+	}
+	allowed, err := services.RoleHasPermission(claims.RoleName, permission)
+	if err != nil {
+		log.Printf("Permission check failed for role %s: %v", claims.RoleName, err)
+		return false
+	}
+	return allowed
+}
@@ -1,12 +1,15 @@
 package models
 
 type EmployeeDetail struct {
-	EmployeeID          string `json:"employeeId"`
-	Role                string `json:"role"`
-	Department          string `json:"department"`
-	Position            string `json:"position"`
-	FirstName           string `json:"firstName"`
-	LastName            string `json:"lastName"`
-	Email               string `json:"email"`
-	PasswordPlaceholder string `json:"password"`
+	EmployeeID            string `json:"employeeId"`
+	Role                  string `json:"role"`
+	Department            string `json:"department"`
+	Position              string `json:"position"`
+	FirstName             string `json:"firstName"`
+	LastName              string `json:"lastName"`
+	Email                 string `json:"email"`
+	PasswordResetRequired bool   `json:"passwordResetRequired"`
+	RoleID                int    `json:"roleId"`
+	DepartmentID          *int   `json:"departmentId"`
+	PositionID            *int   `json:"positionId"`
 }
\ No newline at end of file
@@ -5,8 +5,16 @@ type NewEmployeeRequest struct {
 	LastName  string `json:"lastName" binding:"required"`
 	Email     string `json:"email" binding:"required,email"`
 	Password  string `json:"password" binding:"required"`
-	Role      string `json:"role" binding:"required"`   
+	Role      string `json:"role"`   
 	Department string `json:"department"` 
+	Section   string `json:"section"`
 	Position  string `json:"position"`  
 	EmployeeID string `json:"employeeId" binding:"required"`
-}
\ No newline at end of file
+
+	// Master-data IDs, preferred over the display names above. One of Role
+	// and RoleID is required.
+	RoleID       int `json:"roleId"`
+	DepartmentID int `json:"departmentId"`
+	SectionID    int `json:"sectionId"`
+	PositionID   int `json:"positionId"`
+}
@@ -1,12 +1,54 @@
 package models
 
+import "github.com/golang-jwt/jwt/v5"
+
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required"`
 	Password string `json:"password" binding:"required"`
 }
 
 type AuthResponse struct {
-	Token string `json:"token"`
-	Role  string `json:"role"`
-	Email string `json:"email"`
-}
\ No newline at end of file
+	Token         string   `json:"token,omitempty"`
+	RefreshToken  string   `json:"refreshToken,omitempty"`
+	ExpiresIn     int      `json:"expiresIn,omitempty"`
+	Role          string   `json:"role"`
+	Email         string   `json:"email"`
+	RecoveryCodes []string `json:"recoveryCodes,omitempty"`
+
+	MFARequired           bool   `json:"mfaRequired,omitempty"`
+	MFAEnrollmentRequired bool   `json:"mfaEnrollmentRequired,omitempty"`
+	ChallengeToken        string `json:"challengeToken,omitempty"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+type AuthClaims struct {
+	EmployeeID string `json:"employee_id"`
+	Email      string `json:"email"`
+	RoleName   string `json:"role_name"`
+	SessionID  string `json:"sid,omitempty"`
+	TokenUse   string `json:"token_use"`
+	jwt.RegisteredClaims
+}
+
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
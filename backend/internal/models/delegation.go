@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+type Delegation struct {
+	DelegationID int       `json:"delegationId"`
+	EmployeeID   string    `json:"employeeId"`
+	EmployeeName string    `json:"employeeName"`
+	DelegateID   string    `json:"delegateId"`
+	DelegateName string    `json:"delegateName"`
+	StartDate    string    `json:"startDate"`
+	EndDate      string    `json:"endDate"`
+	Reason       string    `json:"reason"`
+	Status       string    `json:"status"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type CreateDelegationRequest struct {
+	DelegateID string `json:"delegateId" binding:"required"`
+	StartDate  string `json:"startDate" binding:"required,datetime=2006-01-02"`
+	EndDate    string `json:"endDate" binding:"required,datetime=2006-01-02"`
+	Reason     string `json:"reason" binding:"max=255"`
+}
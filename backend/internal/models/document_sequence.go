@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// DocTypeManpowerRequest numbers submitted manpower requests.
+const DocTypeManpowerRequest = "MANPOWER_REQUEST"
+
+const (
+	SequenceResetNever   = "NEVER"
+	SequenceResetYearly  = "YEARLY"
+	SequenceResetMonthly = "MONTHLY"
+	SequenceResetDaily   = "DAILY"
+)
+
+type DocumentSequence struct {
+	DocType     string    `json:"docType"`
+	Pattern     string    `json:"pattern"`
+	ResetPeriod string    `json:"resetPeriod"`
+	LastValue   int       `json:"lastValue"`
+	NextNumber  string    `json:"nextNumber"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type DocumentSequenceInput struct {
+	Pattern     string `json:"pattern" binding:"required,max=100"`
+	ResetPeriod string `json:"resetPeriod" binding:"required,oneof=NEVER YEARLY MONTHLY DAILY"`
+}
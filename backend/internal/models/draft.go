@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// ManpowerRequestDraft is a saved, possibly incomplete, request form.
+// Selections are returned both by name and by ID.
+type ManpowerRequestDraft struct {
+	DraftID               int                   `json:"draftId"`
+	DocumentDate          string                `json:"documentDate"`
+	Department            string                `json:"department"`
+	EmploymentType        string                `json:"employmentType"`
+	ContractType          string                `json:"contractType"`
+	RequestReason         string                `json:"requestReason"`
+	PositionId            string                `json:"positionId"`
+	PositionRequire       string                `json:"positionRequire"`
+	AgeFrom               string                `json:"ageFrom"`
+	AgeTo                 string                `json:"ageTo"`
+	Gender                string                `json:"gender"`
+	Nationality           string                `json:"nationality"`
+	Experience            string                `json:"experience"`
+	EducationLevel        string                `json:"educationLevel"`
+	SpecialQualifications string                `json:"specialQualifications"`
+	Headcount             int                   `json:"headcount"`
+	Lines                 []ManpowerRequestLine `json:"lines"`
+	CreatedAt             time.Time             `json:"createdAt"`
+	UpdatedAt             time.Time             `json:"updatedAt"`
+
+	DepartmentID      *int `json:"departmentId"`
+	EmploymentTypeID  *int `json:"employmentTypeId"`
+	ContractTypeID    *int `json:"contractTypeId"`
+	RequestReasonID   *int `json:"requestReasonId"`
+	PositionRequireID *int `json:"positionRequireId"`
+	GenderID          *int `json:"genderId"`
+	NationalityID     *int `json:"nationalityId"`
+	ExperienceID      *int `json:"experienceId"`
+	EducationLevelID  *int `json:"educationLevelId"`
+}
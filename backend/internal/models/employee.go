@@ -6,4 +6,13 @@ type UserProfile struct {
 	Email      string `json:"email"`
 	Role       string `json:"role"`
 	Department string `json:"department"`
-}
\ No newline at end of file
+}
+
+type CurrentUser struct {
+	UserProfile
+	EmployeeID      string   `json:"employeeId"`
+	Position        string   `json:"position"`
+	Section         string   `json:"section"`
+	ProfileImageURL string   `json:"profileImageUrl"`
+	Permissions     []string `json:"permissions"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+const (
+	HolidaySourceManual = "MANUAL"
+	HolidaySourceICS    = "ICS"
+)
+
+type Holiday struct {
+	HolidayID int       `json:"holidayId"`
+	Date      string    `json:"date"`
+	Name      string    `json:"name"`
+	Source    string    `json:"source"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type HolidayInput struct {
+	Date string `json:"date" binding:"required,datetime=2006-01-02"`
+	Name string `json:"name" binding:"required,max=150"`
+}
+
+type HolidayImportResult struct {
+	Imported int `json:"imported"`
+	Updated  int `json:"updated"`
+}
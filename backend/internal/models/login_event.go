@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+type LoginEvent struct {
+	EventID       int       `json:"eventId"`
+	Email         string    `json:"email"`
+	EmployeeID    string    `json:"employeeId"`
+	Success       bool      `json:"success"`
+	FailureReason string    `json:"failureReason"`
+	IPAddress     string    `json:"ipAddress"`
+	UserAgent     string    `json:"userAgent"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+type LoginEventFilter struct {
+	Email      string
+	EmployeeID string
+	IPAddress  string
+	Success    *bool
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+	Offset     int
+}
@@ -0,0 +1,144 @@
+package models
+
+import "time"
+
+type ManpowerRequestRecord struct {
+	DocNumber             string
+	EmployeeID            string
+	DocDate               time.Time
+	DeptID                int
+	PosID                 int
+	EmploymentTypeID      int
+	ContractTypeID        int
+	ReasonID              int
+	RequiredPositionCode  string
+	RequiredPositionName  string
+	MinAge                int
+	MaxAge                int
+	GenderID              int
+	NationalityID         int
+	ExperienceID          int
+	EducationLevelID      int
+	SpecialQualifications string
+	Headcount             int
+	Lines                 []ManpowerRequestLineRecord
+
+	// CreatedBy is the employee who submitted the record, which differs from
+	// EmployeeID when a request is raised on someone else's behalf.
+	CreatedBy string
+}
+
+// ManpowerRequestLineRecord is one position asked for on a request.
+type ManpowerRequestLineRecord struct {
+	PosID          int
+	PositionCode   string
+	PositionName   string
+	Quantity       int
+	Qualifications string
+	TargetHireDate time.Time
+}
+
+// Fulfillment states of a request line, derived from how much of its quantity
+// has been filled.
+const (
+	LineOpen    = "OPEN"
+	LinePartial = "PARTIAL"
+	LineFilled  = "FILLED"
+)
+
+type ManpowerRequestLine struct {
+	LineID            int    `json:"lineId"`
+	LineNo            int    `json:"lineNo"`
+	PositionRequireID *int   `json:"positionRequireId"`
+	PositionRequire   string `json:"positionRequire"`
+	PositionId        string `json:"positionId"`
+	Quantity          int    `json:"quantity"`
+	FilledQuantity    int    `json:"filledQuantity"`
+	Qualifications    string `json:"qualifications"`
+	TargetHireDate    string `json:"targetHireDate"`
+	FillStatus        string `json:"fillStatus"`
+}
+
+type ManpowerRequestFilter struct {
+	LifecycleStatus string
+	DepartmentID    int
+	RequesterID     string
+	DocNumber       string
+	From            *time.Time
+	To              *time.Time
+
+	// ViewerID limits the results to requests the viewer raised or sits on
+	// the approval chain of. It is left empty for requests:read_all.
+	ViewerID string
+	// AwaitingID limits the results to requests with a vote that this
+	// employee, or someone they currently stand in for, still has to cast.
+	AwaitingID string
+
+	Page     int
+	PageSize int
+}
+
+type ManpowerRequestSummary struct {
+	RequestID       int               `json:"requestId"`
+	DocNumber       string            `json:"documentNumber"`
+	DocumentDate    string            `json:"documentDate"`
+	DepartmentID    *int              `json:"departmentId"`
+	Department      string            `json:"department"`
+	PositionRequire string            `json:"positionRequire"`
+	Headcount       int               `json:"headcount"`
+	FilledHeadcount int               `json:"filledHeadcount"`
+	RequesterID     string            `json:"requesterId"`
+	RequesterName   string            `json:"requesterName"`
+	CurrentStatus   string            `json:"currentStatus"`
+	LifecycleStatus string            `json:"lifecycleStatus"`
+	StepStatuses    map[string]string `json:"stepStatuses"`
+	DueAt           *time.Time        `json:"dueAt"`
+	CreatedAt       time.Time         `json:"createdAt"`
+}
+
+type ManpowerRequestList struct {
+	Items    []ManpowerRequestSummary `json:"items"`
+	Total    int                      `json:"total"`
+	Page     int                      `json:"page"`
+	PageSize int                      `json:"pageSize"`
+}
+
+// ManpowerRequestDetail is a submitted request with every master-data
+// reference given by both ID and name, plus its approval workflow.
+type ManpowerRequestDetail struct {
+	RequestID             int                   `json:"requestId"`
+	DocNumber             string                `json:"documentNumber"`
+	DocumentDate          string                `json:"documentDate"`
+	RequesterID           string                `json:"requesterId"`
+	RequesterName         string                `json:"requesterName"`
+	Department            string                `json:"department"`
+	EmploymentType        string                `json:"employmentType"`
+	ContractType          string                `json:"contractType"`
+	RequestReason         string                `json:"requestReason"`
+	PositionId            string                `json:"positionId"`
+	PositionRequire       string                `json:"positionRequire"`
+	AgeFrom               *int                  `json:"ageFrom"`
+	AgeTo                 *int                  `json:"ageTo"`
+	Gender                string                `json:"gender"`
+	Nationality           string                `json:"nationality"`
+	Experience            string                `json:"experience"`
+	EducationLevel        string                `json:"educationLevel"`
+	SpecialQualifications string                `json:"specialQualifications"`
+	Headcount             int                   `json:"headcount"`
+	CurrentStatus         string                `json:"currentStatus"`
+	LifecycleStatus       string                `json:"lifecycleStatus"`
+	CreatedAt             time.Time             `json:"createdAt"`
+	UpdatedAt             time.Time             `json:"updatedAt"`
+	Lines                 []ManpowerRequestLine `json:"lines"`
+	Workflow              *WorkflowState        `json:"workflow"`
+
+	DepartmentID      *int `json:"departmentId"`
+	EmploymentTypeID  *int `json:"employmentTypeId"`
+	ContractTypeID    *int `json:"contractTypeId"`
+	RequestReasonID   *int `json:"requestReasonId"`
+	PositionRequireID *int `json:"positionRequireId"`
+	GenderID          *int `json:"genderId"`
+	NationalityID     *int `json:"nationalityId"`
+	ExperienceID      *int `json:"experienceId"`
+	EducationLevelID  *int `json:"educationLevelId"`
+}
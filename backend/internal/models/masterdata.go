@@ -17,4 +17,22 @@ type MasterDataResponse struct {
 	Experiences     []MasterDataItem `json:"experiences"`
 	EducationLevels []MasterDataItem `json:"educationLevels"`
 	Roles           []MasterDataItem `json:"roles"`
-}
\ No newline at end of file
+}
+
+// FieldError reports one invalid field of a submitted form, by its payload
+// field name. Code is stable for clients to key off; the messages are for
+// display.
+type FieldError struct {
+	Field     string `json:"field"`
+	Code      string `json:"code"`
+	Value     string `json:"value,omitempty"`
+	Message   string `json:"message"`
+	MessageTH string `json:"messageTh"`
+}
+
+// ValidationErrorResponse is the body of every 400 caused by invalid fields.
+type ValidationErrorResponse struct {
+	Error  string       `json:"error"`
+	Code   string       `json:"code"`
+	Fields []FieldError `json:"fields"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+const (
+	NotificationSLAReminder = "SLA_REMINDER"
+	NotificationEscalation  = "ESCALATION"
+)
+
+type Notification struct {
+	NotificationID int        `json:"notificationId"`
+	RequestID      *int       `json:"requestId"`
+	Kind           string     `json:"kind"`
+	Message        string     `json:"message"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	ReadAt         *time.Time `json:"readAt"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type PasswordResetIssued struct {
+	EmployeeID string    `json:"employeeId"`
+	ResetToken string    `json:"resetToken"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+type PasswordResetRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required,min=8"`
+}
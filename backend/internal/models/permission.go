@@ -0,0 +1,29 @@
+package models
+
+const (
+	PermProfileRead     = "profile:read"
+	PermMasterDataRead  = "masterdata:read"
+	PermEmployeesRead   = "employees:read"
+	PermEmployeesWrite  = "employees:write"
+	PermRolesManage     = "roles:manage"
+	PermWorkflowManage  = "workflow:manage"
+	PermCalendarManage  = "calendar:manage"
+	PermSequencesManage = "sequences:manage"
+	PermAuditRead       = "audit:read"
+	PermRequestsCreate  = "requests:create"
+	PermRequestsRead    = "requests:read"
+	PermRequestsApprove = "requests:approve"
+
+	PermRequestsReadAll        = "requests:read_all"
+	PermRequestsFulfill        = "requests:fulfill"
+	PermRequestsCreateOnBehalf = "requests:create_on_behalf"
+)
+
+type RolePermissions struct {
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+}
+
+type SetRolePermissionsRequest struct {
+	Permissions []string `json:"permissions" binding:"required"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Lifecycle states of a manpower request. current_status keeps the Thai
+// label shown to users; lifecycle_status is what transitions are checked
+// against.
+const (
+	RequestDraft      = "DRAFT"
+	RequestSubmitted  = "SUBMITTED"
+	RequestInApproval = "IN_APPROVAL"
+	RequestReturned   = "RETURNED"
+	RequestApproved   = "APPROVED"
+	RequestRejected   = "REJECTED"
+	RequestCancelled  = "CANCELLED"
+	RequestFilled     = "FILLED"
+)
+
+type RequestTransition struct {
+	TransitionID int       `json:"transitionId"`
+	FromStatus   string    `json:"fromStatus"`
+	ToStatus     string    `json:"toStatus"`
+	ActorID      string    `json:"actorId"`
+	ActorName    string    `json:"actorName"`
+	Notes        string    `json:"notes"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type WithdrawRequest struct {
+	Reason string `json:"reason" binding:"max=2000"`
+}
+
+type FillRequest struct {
+	Notes string `json:"notes" binding:"max=2000"`
+}
+
+// FillLineRequest records hires against one line of an approved request.
+type FillLineRequest struct {
+	Quantity int    `json:"quantity" binding:"required,min=1,max=999"`
+	Notes    string `json:"notes" binding:"max=2000"`
+}
@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+const (
+	RoutingActionSkipStep = "SKIP_STEP"
+	RoutingActionAddStep  = "ADD_STEP"
+)
+
+type RoutingRule struct {
+	RuleID           int       `json:"ruleId"`
+	RuleName         string    `json:"ruleName"`
+	Priority         int       `json:"priority"`
+	DeptID           *int      `json:"deptId"`
+	Department       string    `json:"department,omitempty"`
+	EmploymentTypeID *int      `json:"employmentTypeId"`
+	EmploymentType   string    `json:"employmentType,omitempty"`
+	ContractTypeID   *int      `json:"contractTypeId"`
+	ContractType     string    `json:"contractType,omitempty"`
+	ReasonID         *int      `json:"requestReasonId"`
+	RequestReason    string    `json:"requestReason,omitempty"`
+	MinHeadcount     *int      `json:"minHeadcount"`
+	MaxHeadcount     *int      `json:"maxHeadcount"`
+	Action           string    `json:"action"`
+	StepCode         string    `json:"stepCode"`
+	Status           string    `json:"status"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+}
+
+type RoutingRuleInput struct {
+	RuleName         string `json:"ruleName" binding:"required,max=150"`
+	Priority         int    `json:"priority"`
+	DeptID           *int   `json:"deptId"`
+	EmploymentTypeID *int   `json:"employmentTypeId"`
+	ContractTypeID   *int   `json:"contractTypeId"`
+	ReasonID         *int   `json:"requestReasonId"`
+	MinHeadcount     *int   `json:"minHeadcount" binding:"omitempty,min=1"`
+	MaxHeadcount     *int   `json:"maxHeadcount" binding:"omitempty,min=1"`
+	Action           string `json:"action" binding:"required,oneof=SKIP_STEP ADD_STEP"`
+	StepCode         string `json:"stepCode" binding:"required"`
+	Status           string `json:"status" binding:"omitempty,oneof=Active Inactive"`
+}
+
+type RoutingDryRunRequest struct {
+	DeptID           int    `json:"deptId" binding:"required"`
+	EmploymentTypeID int    `json:"employmentTypeId" binding:"required"`
+	ContractTypeID   int    `json:"contractTypeId" binding:"required"`
+	ReasonID         int    `json:"requestReasonId" binding:"required"`
+	Headcount        int    `json:"headcount"`
+	RequesterID      string `json:"requesterId"`
+}
+
+type PlannedApprovalStep struct {
+	StepOrder         int      `json:"stepOrder"`
+	StepCode          string   `json:"stepCode"`
+	StepName          string   `json:"stepName"`
+	PendingStatus     string   `json:"pendingStatus"`
+	CompletionPolicy  string   `json:"completionPolicy"`
+	RequiredApprovals int      `json:"requiredApprovals"`
+	ApproverIDs       []string `json:"approverIds"`
+	Skipped           bool     `json:"skipped"`
+	SkipReason        string   `json:"skipReason,omitempty"`
+}
+
+type RoutingDryRunResult struct {
+	MatchedRules []RoutingRule         `json:"matchedRules"`
+	Steps        []PlannedApprovalStep `json:"steps"`
+}
@@ -0,0 +1,99 @@
+package models
+
+import "time"
+
+const (
+	StepStatusWaiting  = "Waiting"
+	StepStatusPending  = "Pending"
+	StepStatusApproved = "Approved"
+	StepStatusRejected = "Rejected"
+	StepStatusReturned = "Returned"
+	StepStatusSkipped  = "Skipped"
+
+	// StepStatusEscalated marks an approver whose overdue vote was handed
+	// to their superior.
+	StepStatusEscalated = "Escalated"
+)
+
+const (
+	CompletionPolicyAll    = "ALL"
+	CompletionPolicyAny    = "ANY"
+	CompletionPolicyQuorum = "QUORUM"
+)
+
+const (
+	DecisionSubmitted = "Submitted"
+	DecisionApproved  = "Approved"
+	DecisionRejected  = "Rejected"
+	DecisionReturned  = "Returned"
+	DecisionEscalated = "Escalated"
+	DecisionWithdrawn = "Withdrawn"
+	DecisionSkipped   = "Skipped"
+)
+
+const (
+	RequestStatusDraft     = "ฉบับร่าง"
+	RequestStatusApproved  = "อนุมัติแล้ว"
+	RequestStatusRejected  = "ไม่อนุมัติ"
+	RequestStatusReturned  = "ส่งกลับแก้ไข"
+	RequestStatusCancelled = "ยกเลิกแล้ว"
+	RequestStatusFilled    = "จัดหาบุคลากรแล้ว"
+)
+
+type StepApprover struct {
+	ApproverID   string     `json:"approverId"`
+	ApproverName string     `json:"approverName"`
+	Status       string     `json:"status"`
+	DecidedAt    *time.Time `json:"decidedAt"`
+	DelegateID   string     `json:"delegateId,omitempty"`
+	DelegateName string     `json:"delegateName,omitempty"`
+}
+
+type ApprovalStep struct {
+	StepID            int            `json:"stepId"`
+	Round             int            `json:"round"`
+	StepOrder         int            `json:"stepOrder"`
+	StepCode          string         `json:"stepCode"`
+	StepName          string         `json:"stepName"`
+	PendingStatus     string         `json:"pendingStatus"`
+	CompletionPolicy  string         `json:"completionPolicy"`
+	RequiredApprovals int            `json:"requiredApprovals"`
+	ApprovalCount     int            `json:"approvalCount"`
+	Approvers         []StepApprover `json:"approvers"`
+	Status            string         `json:"status"`
+	StartedAt         *time.Time     `json:"startedAt"`
+	CompletedAt       *time.Time     `json:"completedAt"`
+	SLABusinessDays   *int           `json:"slaBusinessDays"`
+	DueAt             *time.Time     `json:"dueAt"`
+	EscalationLevel   int            `json:"escalationLevel"`
+}
+
+type ApprovalHistoryEntry struct {
+	HistoryID      int       `json:"historyId"`
+	StepID         *int      `json:"stepId"`
+	StepName       string    `json:"stepName"`
+	ApproverID     string    `json:"approverId"`
+	ApproverName   string    `json:"approverName"`
+	OnBehalfOf     string    `json:"onBehalfOf,omitempty"`
+	OnBehalfOfName string    `json:"onBehalfOfName,omitempty"`
+	Decision       string    `json:"decision"`
+	Notes          string    `json:"notes"`
+	ApprovalTime   time.Time `json:"approvalTime"`
+}
+
+type WorkflowState struct {
+	RequestID       int                    `json:"requestId"`
+	RequesterID     string                 `json:"requesterId"`
+	CurrentStatus   string                 `json:"currentStatus"`
+	LifecycleStatus string                 `json:"lifecycleStatus"`
+	CurrentStep     *ApprovalStep          `json:"currentStep"`
+	PendingApprover string                 `json:"pendingApprover"`
+	Steps           []ApprovalStep         `json:"steps"`
+	History         []ApprovalHistoryEntry `json:"history"`
+	Transitions     []RequestTransition    `json:"transitions"`
+}
+
+type DecisionRequest struct {
+	Decision string `json:"decision" binding:"required,oneof=approve reject return"`
+	Notes    string `json:"notes" binding:"max=2000"`
+}
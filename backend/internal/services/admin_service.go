@@ -17,7 +17,10 @@ func GetAllEmployees() ([]models.EmployeeDetail, error) {
             e.first_name, 
             e.last_name, 
             e.email,
-            e.password 
+            e.password_reset_required,
+            e.role_id,
+            e.dept_id,
+            e.pos_id
         FROM employees e
         LEFT JOIN roles r ON e.role_id = r.role_id
         LEFT JOIN departments d ON e.dept_id = d.dept_id
@@ -35,6 +38,7 @@ func GetAllEmployees() ([]models.EmployeeDetail, error) {
 
 	var employees []models.EmployeeDetail
 	var deptName, posName sql.NullString
+	var deptID, posID sql.NullInt64
 
 	for rows.Next() {
 		var employee models.EmployeeDetail
@@ -47,7 +51,10 @@ func GetAllEmployees() ([]models.EmployeeDetail, error) {
 			&employee.FirstName,
 			&employee.LastName,
 			&employee.Email,
-			&employee.PasswordPlaceholder,
+			&employee.PasswordResetRequired,
+			&employee.RoleID,
+			&deptID,
+			&posID,
 		)
 		if err != nil {
 			log.Printf("Error scanning employee row: %v", err)
@@ -56,6 +63,8 @@ func GetAllEmployees() ([]models.EmployeeDetail, error) {
 
 		employee.Department = deptName.String
 		employee.Position = posName.String
+		employee.DepartmentID = nullIntPtr(deptID)
+		employee.PositionID = nullIntPtr(posID)
 		employees = append(employees, employee)
 	}
 
@@ -3,47 +3,71 @@ package services
 import (
 	"database/sql"
 	"errors"
-	"fmt"
 	"log"
 	"mantest/backend/internal/database"
 	"mantest/backend/internal/models"
+	"mantest/backend/internal/validation"
 	"strings"
 )
 
+var ErrDuplicateEmployee = errors.New("Employee ID or Email already exists in the system.")
+
+// InvalidFieldsError carries every field of a submission that failed
+// validation, so all of them can be reported at once.
+type InvalidFieldsError struct {
+	Fields []models.FieldError
+}
+
+func (e *InvalidFieldsError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		messages[i] = f.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
 func CreateNewEmployee(req *models.NewEmployeeRequest) error {
-	roleName := strings.ToUpper(req.Role)
-	roleID, err := GetIDByName("role", roleName)
+	lookups := []MasterDataLookup{
+		{Field: "role", Table: "role", ID: req.RoleID, Name: req.Role},
+		{Field: "department", Table: "department", ID: req.DepartmentID, Name: req.Department},
+		{Field: "section", Table: "section", ID: req.SectionID, Name: req.Section},
+		{Field: "position", Table: "position", ID: req.PositionID, Name: req.Position},
+	}
+	items, invalid, err := ResolveMasterData(lookups)
 	if err != nil {
-		return fmt.Errorf("invalid role name: %s", req.Role)
+		log.Printf("Error resolving master data for new employee %s: %v", req.EmployeeID, err)
+		return errors.New("Failed to save new employee to database.")
 	}
-
-	var deptID, posID int
-	if req.Department != "" {
-		deptID, err = GetIDByName("department", strings.ToUpper(req.Department))
-		if err != nil {
-			return fmt.Errorf("invalid department name: %s", req.Department)
-		}
+	if lookups[0].empty() {
+		invalid = append(invalid, validation.NewFieldError("role", validation.CodeRequired, ""))
 	}
-	if req.Position != "" {
-		posID, err = GetIDByName("position", strings.ToUpper(req.Position))
-		if err != nil {
-			return fmt.Errorf("invalid position name: %s", req.Position)
-		}
+	if len(invalid) > 0 {
+		return &InvalidFieldsError{Fields: invalid}
 	}
+	roleID, deptID, sectionID, posID := items[0].ID, items[1].ID, items[2].ID, items[3].ID
 
-	var sqlDeptID, sqlPosID sql.NullInt32
+	var sqlDeptID, sqlPosID, sqlSectionID sql.NullInt32
 	if deptID != 0 {
 		sqlDeptID = sql.NullInt32{Int32: int32(deptID), Valid: true}
 	}
 	if posID != 0 {
 		sqlPosID = sql.NullInt32{Int32: int32(posID), Valid: true}
 	}
+	if sectionID != 0 {
+		sqlSectionID = sql.NullInt32{Int32: int32(sectionID), Valid: true}
+	}
+
+	passwordHash, err := HashPassword(req.Password)
+	if err != nil {
+		log.Printf("Failed to hash password for new employee %s: %v", req.EmployeeID, err)
+		return errors.New("Failed to save new employee to database.")
+	}
 
 	query := `
 		INSERT INTO employees (
-			employee_id, first_name, last_name, email, password, pos_id, dept_id, role_id
+			employee_id, first_name, last_name, email, password, pos_id, dept_id, section_id, role_id
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 
 	_, err = database.DB.Exec(query,
@@ -51,16 +75,17 @@ func CreateNewEmployee(req *models.NewEmployeeRequest) error {
 		req.FirstName,
 		req.LastName,
 		req.Email,
-		req.Password,
+		passwordHash,
 		sqlPosID,
 		sqlDeptID,
+		sqlSectionID,
 		roleID,
 	)
 
 	if err != nil {
 		log.Printf("SQL INSERT Employee Error: %v", err)
 		if strings.Contains(err.Error(), "duplicate key value violates unique constraint") {
-			return errors.New("Employee ID or Email already exists in the system.")
+			return ErrDuplicateEmployee
 		}
 		return errors.New("Failed to save new employee to database.")
 	}
@@ -5,53 +5,117 @@ import (
 	"errors"
 	"log"
 	"mantest/backend/internal/database"
+	"mantest/backend/internal/models"
 	"strings"
-	"time"
-
-	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtSecret = []byte("YOUR_ULTRA_SECURE_SECRET_KEY")
+var (
+	ErrTokenExpired          = errors.New("token has expired")
+	ErrTokenInvalid          = errors.New("invalid token")
+	ErrPasswordResetRequired = errors.New("password reset required")
+)
 
-func Authenticate(email, password string) (string, string, string, error) {
+func Authenticate(email, password string, client models.ClientInfo) (*models.AuthResponse, error) {
 	email = strings.ToLower(email)
 	var employeeID, storedPassword, roleName string
+	var resetRequired, mfaEnabled, mfaRequired bool
+
+	throttled, err := checkLoginAllowed(email, client.IPAddress)
+	if err != nil {
+		log.Printf("Database error checking login throttle for email %s: %v", email, err)
+		return nil, errors.New("database error")
+	}
+	if throttled != nil {
+		reason := LoginFailureThrottled
+		if throttled.Locked {
+			reason = LoginFailureLockedOut
+		}
+		log.Printf("Authentication rejected for email %s from %s: %v", email, client.IPAddress, throttled)
+		recordLoginEvent(email, "", false, reason, client)
+		return nil, throttled
+	}
 
 	query := `
-        SELECT e.employee_id, e.password, r.role_name
+        SELECT e.employee_id, e.password, r.role_name, e.password_reset_required,
+               COALESCE(m.enabled, FALSE), r.mfa_required
         FROM employees e
         JOIN roles r ON e.role_id = r.role_id
+        LEFT JOIN employee_mfa m ON m.employee_id = e.employee_id
         WHERE e.email = $1 AND e.status = 'Active'
     `
 
-	err := database.DB.QueryRow(query, email).Scan(&employeeID, &storedPassword, &roleName)
+	err = database.DB.QueryRow(query, email).Scan(
+		&employeeID, &storedPassword, &roleName, &resetRequired, &mfaEnabled, &mfaRequired,
+	)
 	if err != nil {
 		if err == sql.ErrNoRows {
+			rejectUnknownPassword(password)
 			log.Printf("Authentication failed for email %s: user not found or inactive", email)
-			return "", "", "", errors.New("authentication failed: invalid credentials")
+			registerLoginFailure(email, client.IPAddress)
+			recordLoginEvent(email, "", false, LoginFailureUnknownAccount, client)
+			return nil, errors.New("authentication failed: invalid credentials")
 		}
 		log.Printf("Database error during authentication for email %s: %v", email, err)
-		return "", "", "", errors.New("database error")
+		recordLoginEvent(email, "", false, LoginFailureError, client)
+		return nil, errors.New("database error")
 	}
 
-	if storedPassword != password {
+	match, needsRehash := VerifyPassword(storedPassword, password)
+	if !match {
 		log.Printf("Authentication failed for email %s: incorrect password", email)
-		return "", "", "", errors.New("authentication failed: invalid credentials")
+		registerLoginFailure(email, client.IPAddress)
+		recordLoginEvent(email, employeeID, false, LoginFailureBadPassword, client)
+		return nil, errors.New("authentication failed: invalid credentials")
+	}
+	if resetRequired {
+		log.Printf("Authentication blocked for email %s: password reset required", email)
+		recordLoginEvent(email, employeeID, false, LoginFailureResetRequired, client)
+		return nil, ErrPasswordResetRequired
+	}
+	if needsRehash {
+		rehashPassword(employeeID, password)
 	}
 
-	claims := jwt.MapClaims{
-		"employee_id": employeeID,
-		"email":       email,
-		"role_name":   roleName,
-		"exp":         time.Now().Add(time.Hour * 24).Unix(),
+	if mfaEnabled || mfaRequired {
+		challenge, err := issueMFAChallenge(employeeID, email, roleName)
+		if err != nil {
+			log.Printf("Failed to issue MFA challenge for email %s: %v", email, err)
+			return nil, errors.New("failed to generate token")
+		}
+		log.Printf("Password verified for email %s, awaiting second factor", email)
+		return &models.AuthResponse{
+			Role:                  roleName,
+			Email:                 email,
+			MFARequired:           true,
+			MFAEnrollmentRequired: !mfaEnabled,
+			ChallengeToken:        challenge,
+		}, nil
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(jwtSecret)
+	return finishLogin(employeeID, email, roleName, client)
+}
+
+func finishLogin(employeeID, email, roleName string, client models.ClientInfo) (*models.AuthResponse, error) {
+	response, err := startSession(employeeID, email, roleName, client)
 	if err != nil {
 		log.Printf("Failed to generate token for email %s: %v", email, err)
-		return "", "", "", errors.New("failed to generate token")
+		recordLoginEvent(email, employeeID, false, LoginFailureError, client)
+		return nil, errors.New("failed to generate token")
+	}
+
+	clearLoginFailures(email)
+	recordLoginEvent(email, employeeID, true, "", client)
+	return response, nil
+}
+
+func ParseToken(tokenString string) (*models.AuthClaims, error) {
+	claims := &models.AuthClaims{}
+	if err := parseSignedClaims(tokenString, claims); err != nil {
+		return nil, err
 	}
 
-	return tokenString, roleName, email, nil
+	if claims.TokenUse != tokenUseAccess || claims.EmployeeID == "" {
+		return nil, ErrTokenInvalid
+	}
+	return claims, nil
 }
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"mantest/backend/internal/database"
+	"mantest/backend/internal/models"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	holidayDateLayout = "2006-01-02"
+	holidayCacheTTL   = 10 * time.Minute
+)
+
+var (
+	ErrHolidayNotFound     = errors.New("holiday not found")
+	ErrHolidayDateTaken    = errors.New("a holiday already exists on that date")
+	ErrInvalidHolidayDate  = errors.New("invalid holiday date")
+	ErrInvalidCalendarFile = errors.New("invalid calendar file")
+)
+
+// businessLocation is the zone working days are counted in. Thailand does
+// not observe daylight saving, so a fixed offset is enough and avoids
+// depending on tzdata in the runtime image.
+var businessLocation = time.FixedZone("ICT", 7*60*60)
+
+var (
+	holidayCache         map[string]bool
+	holidayCacheLoadedAt time.Time
+	holidayCacheMu       sync.RWMutex
+)
+
+func loadHolidaySet() (map[string]bool, error) {
+	rows, err := database.DB.Query(`SELECT holiday_date FROM public_holidays`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	holidays := make(map[string]bool)
+	for rows.Next() {
+		var date time.Time
+		if err := rows.Scan(&date); err != nil {
+			return nil, err
+		}
+		holidays[date.Format(holidayDateLayout)] = true
+	}
+	return holidays, rows.Err()
+}
+
+// getHolidaySet returns the cached holiday dates. If they cannot be loaded
+// the last known set is used, so deadlines degrade to weekends-only rather
+// than failing outright.
+func getHolidaySet() map[string]bool {
+	holidayCacheMu.RLock()
+	cached, loadedAt := holidayCache, holidayCacheLoadedAt
+	holidayCacheMu.RUnlock()
+	if cached != nil && time.Since(loadedAt) < holidayCacheTTL {
+		return cached
+	}
+
+	holidays, err := loadHolidaySet()
+	if err != nil {
+		log.Printf("Error loading public holidays: %v", err)
+		return cached
+	}
+
+	holidayCacheMu.Lock()
+	holidayCache, holidayCacheLoadedAt = holidays, time.Now()
+	holidayCacheMu.Unlock()
+	return holidays
+}
+
+func invalidateHolidayCache() {
+	holidayCacheMu.Lock()
+	holidayCache = nil
+	holidayCacheMu.Unlock()
+}
+
+func isBusinessDay(t time.Time, holidays map[string]bool) bool {
+	local := t.In(businessLocation)
+	switch local.Weekday() {
+	case time.Saturday, time.Sunday:
+		return false
+	}
+	return !holidays[local.Format(holidayDateLayout)]
+}
+
+// BusinessNow is the current time in the zone working days are counted in.
+func BusinessNow() time.Time {
+	return time.Now().In(businessLocation)
+}
+
+// IsBusinessDay reports whether t falls on a working day in Thailand.
+func IsBusinessDay(t time.Time) bool {
+	return isBusinessDay(t, getHolidaySet())
+}
+
+// AddBusinessDays returns the time the given number of working days after
+// from, keeping the time of day. Weekends and public holidays are skipped.
+func AddBusinessDays(from time.Time, days int) time.Time {
+	holidays := getHolidaySet()
+	t := from.In(businessLocation)
+	for days > 0 {
+		t = t.AddDate(0, 0, 1)
+		if isBusinessDay(t, holidays) {
+			days--
+		}
+	}
+	return t
+}
+
+// BusinessDaysBetween counts the working days after start up to and
+// including end, so that BusinessDaysBetween(t, AddBusinessDays(t, n)) == n.
+// The result is negative when end is before start.
+func BusinessDaysBetween(start, end time.Time) int {
+	if end.Before(start) {
+		return -BusinessDaysBetween(end, start)
+	}
+
+	holidays := getHolidaySet()
+	startDay := start.In(businessLocation)
+	endDate := end.In(businessLocation).Format(holidayDateLayout)
+
+	count := 0
+	for t := startDay.AddDate(0, 0, 1); t.Format(holidayDateLayout) <= endDate; t = t.AddDate(0, 0, 1) {
+		if isBusinessDay(t, holidays) {
+			count++
+		}
+	}
+	return count
+}
+
+func GetHolidays(year int) ([]models.Holiday, error) {
+	rows, err := database.DB.Query(`
+		SELECT holiday_id, holiday_date, holiday_name, source, created_at
+		FROM public_holidays
+		WHERE EXTRACT(YEAR FROM holiday_date) = $1
+		ORDER BY holiday_date ASC
+	`, year)
+	if err != nil {
+		log.Printf("Error querying holidays for %d: %v", year, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	holidays := []models.Holiday{}
+	for rows.Next() {
+		var h models.Holiday
+		var date time.Time
+		if err := rows.Scan(&h.HolidayID, &date, &h.Name, &h.Source, &h.CreatedAt); err != nil {
+			log.Printf("Error scanning holiday row: %v", err)
+			return nil, err
+		}
+		h.Date = date.Format(holidayDateLayout)
+		holidays = append(holidays, h)
+	}
+	return holidays, rows.Err()
+}
+
+func CreateHoliday(input *models.HolidayInput) (int, error) {
+	date, err := time.Parse(holidayDateLayout, input.Date)
+	if err != nil {
+		return 0, ErrInvalidHolidayDate
+	}
+
+	var holidayID int
+	err = database.DB.QueryRow(`
+		INSERT INTO public_holidays (holiday_date, holiday_name, source)
+		VALUES ($1, $2, $3)
+		RETURNING holiday_id
+	`, date, strings.TrimSpace(input.Name), models.HolidaySourceManual).Scan(&holidayID)
+	if err != nil {
+		log.Printf("Error creating holiday on %s: %v", input.Date, err)
+		if strings.Contains(err.Error(), "public_holidays_holiday_date_key") {
+			return 0, fmt.Errorf("%w: %s", ErrHolidayDateTaken, input.Date)
+		}
+		return 0, errors.New("Failed to save holiday.")
+	}
+
+	invalidateHolidayCache()
+	return holidayID, nil
+}
+
+func UpdateHoliday(holidayID int, input *models.HolidayInput) error {
+	date, err := time.Parse(holidayDateLayout, input.Date)
+	if err != nil {
+		return ErrInvalidHolidayDate
+	}
+
+	result, err := database.DB.Exec(`
+		UPDATE public_holidays SET holiday_date = $1, holiday_name = $2
+		WHERE holiday_id = $3
+	`, date, strings.TrimSpace(input.Name), holidayID)
+	if err != nil {
+		log.Printf("Error updating holiday %d: %v", holidayID, err)
+		if strings.Contains(err.Error(), "public_holidays_holiday_date_key") {
+			return fmt.Errorf("%w: %s", ErrHolidayDateTaken, input.Date)
+		}
+		return errors.New("Failed to update holiday.")
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrHolidayNotFound
+	}
+
+	invalidateHolidayCache()
+	return nil
+}
+
+func DeleteHoliday(holidayID int) error {
+	result, err := database.DB.Exec(`DELETE FROM public_holidays WHERE holiday_id = $1`, holidayID)
+	if err != nil {
+		log.Printf("Error deleting holiday %d: %v", holidayID, err)
+		return errors.New("Failed to delete holiday.")
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrHolidayNotFound
+	}
+
+	invalidateHolidayCache()
+	return nil
+}
+
+// ImportHolidaysICS adds every all-day event in an iCalendar file as a
+// holiday. Dates that already exist are renamed to the imported summary.
+func ImportHolidaysICS(data []byte) (*models.HolidayImportResult, error) {
+	events, err := parseICSEvents(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCalendarFile, err)
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("%w: the file contains no events", ErrInvalidCalendarFile)
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result := &models.HolidayImportResult{}
+	for _, event := range events {
+		for _, date := range event.dates() {
+			var inserted bool
+			err := tx.QueryRow(`
+				INSERT INTO public_holidays (holiday_date, holiday_name, source)
+				VALUES ($1, $2, $3)
+				ON CONFLICT (holiday_date) DO UPDATE SET holiday_name = EXCLUDED.holiday_name
+				RETURNING (xmax = 0)
+			`, date, event.summary, models.HolidaySourceICS).Scan(&inserted)
+			if err != nil {
+				log.Printf("Error importing holiday on %s: %v", date.Format(holidayDateLayout), err)
+				return nil, errors.New("Failed to import holidays.")
+			}
+			if inserted {
+				result.Imported++
+			} else {
+				result.Updated++
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	invalidateHolidayCache()
+	return result, nil
+}
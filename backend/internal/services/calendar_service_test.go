@@ -0,0 +1,112 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+// useHolidays primes the holiday cache so the calendar can be exercised
+// without a database.
+func useHolidays(t *testing.T, dates ...string) {
+	t.Helper()
+	holidays := make(map[string]bool, len(dates))
+	for _, d := range dates {
+		holidays[d] = true
+	}
+	holidayCacheMu.Lock()
+	holidayCache, holidayCacheLoadedAt = holidays, time.Now()
+	holidayCacheMu.Unlock()
+	t.Cleanup(invalidateHolidayCache)
+}
+
+func businessDate(t *testing.T, value string) time.Time {
+	t.Helper()
+	d, err := time.ParseInLocation("2006-01-02 15:04", value, businessLocation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestIsBusinessDay(t *testing.T) {
+	useHolidays(t, "2026-04-13")
+
+	tests := []struct {
+		at   string
+		want bool
+	}{
+		{"2026-04-10 09:00", true},  // Friday
+		{"2026-04-11 09:00", false}, // Saturday
+		{"2026-04-12 09:00", false}, // Sunday
+		{"2026-04-13 09:00", false}, // holiday
+		{"2026-04-14 09:00", true},
+	}
+	for _, tt := range tests {
+		if got := IsBusinessDay(businessDate(t, tt.at)); got != tt.want {
+			t.Errorf("IsBusinessDay(%s) = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+
+	// 20:00 UTC on Friday is already Saturday in Thailand.
+	if IsBusinessDay(time.Date(2026, time.April, 10, 20, 0, 0, 0, time.UTC)) {
+		t.Error("IsBusinessDay used the UTC date instead of the Thai one")
+	}
+}
+
+func TestAddBusinessDays(t *testing.T) {
+	useHolidays(t, "2026-04-13", "2026-04-14", "2026-04-15")
+
+	tests := []struct {
+		name string
+		from string
+		days int
+		want string
+	}{
+		{"zero days", "2026-04-08 10:30", 0, "2026-04-08 10:30"},
+		{"within the week", "2026-04-06 10:30", 2, "2026-04-08 10:30"},
+		{"over a weekend", "2026-04-03 10:30", 1, "2026-04-06 10:30"},
+		{"from a Saturday", "2026-04-04 10:30", 1, "2026-04-06 10:30"},
+		{"over a weekend and holidays", "2026-04-10 10:30", 1, "2026-04-16 10:30"},
+		{"across holidays", "2026-04-09 08:00", 3, "2026-04-17 08:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AddBusinessDays(businessDate(t, tt.from), tt.days)
+			if want := businessDate(t, tt.want); !got.Equal(want) {
+				t.Errorf("AddBusinessDays(%s, %d) = %s, want %s", tt.from, tt.days, got, want)
+			}
+		})
+	}
+}
+
+func TestBusinessDaysBetween(t *testing.T) {
+	useHolidays(t, "2026-04-13", "2026-04-14", "2026-04-15")
+
+	tests := []struct {
+		name       string
+		start, end string
+		want       int
+	}{
+		{"same day", "2026-04-08 09:00", "2026-04-08 17:00", 0},
+		{"next day", "2026-04-08 17:00", "2026-04-09 09:00", 1},
+		{"over a weekend", "2026-04-03 09:00", "2026-04-06 09:00", 1},
+		{"ending on a weekend", "2026-04-09 09:00", "2026-04-12 09:00", 1},
+		{"over a weekend and holidays", "2026-04-10 09:00", "2026-04-17 09:00", 2},
+		{"reversed", "2026-04-17 09:00", "2026-04-10 09:00", -2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BusinessDaysBetween(businessDate(t, tt.start), businessDate(t, tt.end))
+			if got != tt.want {
+				t.Errorf("BusinessDaysBetween(%s, %s) = %d, want %d", tt.start, tt.end, got, tt.want)
+			}
+		})
+	}
+
+	from := businessDate(t, "2026-04-09 11:00")
+	for n := 0; n <= 10; n++ {
+		if got := BusinessDaysBetween(from, AddBusinessDays(from, n)); got != n {
+			t.Errorf("BusinessDaysBetween(t, AddBusinessDays(t, %d)) = %d", n, got)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"mantest/backend/internal/database"
+	"mantest/backend/internal/models"
+	"strings"
+)
+
+var ErrNotesRequired = errors.New("notes are required when returning a request for revision")
+
+var decisionActions = map[string]string{
+	"approve": models.DecisionApproved,
+	"reject":  models.DecisionRejected,
+	"return":  models.DecisionReturned,
+}
+
+func DecideRequest(requestID int, approverID, action, notes string) error {
+	decision, ok := decisionActions[action]
+	if !ok {
+		return errors.New("decision must be one of approve, reject or return")
+	}
+	notes = strings.TrimSpace(notes)
+	if decision == models.DecisionReturned && notes == "" {
+		return ErrNotesRequired
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var locked int
+	err = tx.QueryRow(`SELECT request_id FROM manpower_requests WHERE request_id = $1 FOR UPDATE`, requestID).Scan(&locked)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrRequestNotFound
+		}
+		return err
+	}
+
+	if err := AdvanceWorkflow(tx, requestID, approverID, decision, notes); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	log.Printf("Request %d: %s by %s", requestID, decision, approverID)
+	return nil
+}
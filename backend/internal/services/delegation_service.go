@@ -0,0 +1,162 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"mantest/backend/internal/database"
+	"mantest/backend/internal/models"
+	"time"
+)
+
+var (
+	ErrDelegationNotFound = errors.New("delegation not found")
+	ErrDelegationOverlap  = errors.New("an active delegation already covers part of this date range")
+	ErrInvalidDelegation  = errors.New("invalid delegation")
+)
+
+const delegationDateLayout = "2006-01-02"
+
+// activeDelegation is the SQL condition for a delegation row, under the given
+// alias, that is in force today.
+func activeDelegation(alias string) string {
+	return alias + ".status = 'Active' AND CURRENT_DATE BETWEEN " + alias + ".start_date AND " + alias + ".end_date"
+}
+
+func CreateDelegation(employeeID string, input *models.CreateDelegationRequest) (int, error) {
+	if input.DelegateID == employeeID {
+		return 0, fmt.Errorf("%w: you cannot delegate to yourself", ErrInvalidDelegation)
+	}
+
+	startDate, err := time.Parse(delegationDateLayout, input.StartDate)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid start date", ErrInvalidDelegation)
+	}
+	endDate, err := time.Parse(delegationDateLayout, input.EndDate)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid end date", ErrInvalidDelegation)
+	}
+	if endDate.Before(startDate) {
+		return 0, fmt.Errorf("%w: end date must not be before start date", ErrInvalidDelegation)
+	}
+	if endDate.Before(time.Now().Truncate(24 * time.Hour)) {
+		return 0, fmt.Errorf("%w: end date is already in the past", ErrInvalidDelegation)
+	}
+
+	var delegateRole string
+	err = database.DB.QueryRow(`
+		SELECT r.role_name FROM employees e
+		JOIN roles r ON e.role_id = r.role_id
+		WHERE e.employee_id = $1 AND e.status = 'Active'
+	`, input.DelegateID).Scan(&delegateRole)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("%w: delegate is not an active employee", ErrInvalidDelegation)
+	}
+	if err != nil {
+		return 0, err
+	}
+	canApprove, err := RoleHasPermission(delegateRole, models.PermRequestsApprove)
+	if err != nil {
+		return 0, err
+	}
+	if !canApprove {
+		return 0, fmt.Errorf("%w: delegate is not allowed to approve requests", ErrInvalidDelegation)
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Serialise delegation changes per employee so the overlap check holds.
+	if _, err := tx.Exec(`SELECT employee_id FROM employees WHERE employee_id = $1 FOR UPDATE`, employeeID); err != nil {
+		return 0, err
+	}
+
+	var overlaps bool
+	err = tx.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM approver_delegations
+			WHERE employee_id = $1 AND status = 'Active'
+			  AND start_date <= $3 AND end_date >= $2
+		)
+	`, employeeID, startDate, endDate).Scan(&overlaps)
+	if err != nil {
+		return 0, err
+	}
+	if overlaps {
+		return 0, ErrDelegationOverlap
+	}
+
+	var delegationID int
+	err = tx.QueryRow(`
+		INSERT INTO approver_delegations (employee_id, delegate_id, start_date, end_date, reason)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING delegation_id
+	`, employeeID, input.DelegateID, startDate, endDate, input.Reason).Scan(&delegationID)
+	if err != nil {
+		log.Printf("Error creating delegation for employee %s: %v", employeeID, err)
+		return 0, errors.New("Failed to save delegation.")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return delegationID, nil
+}
+
+// GetDelegations returns the delegations the employee has given as well as
+// those they have been given, newest first.
+func GetDelegations(employeeID string) ([]models.Delegation, error) {
+	rows, err := database.DB.Query(`
+		SELECT d.delegation_id, d.employee_id, COALESCE(e.first_name || ' ' || e.last_name, ''),
+		       d.delegate_id, COALESCE(de.first_name || ' ' || de.last_name, ''),
+		       d.start_date, d.end_date, COALESCE(d.reason, ''), d.status, d.created_at
+		FROM approver_delegations d
+		LEFT JOIN employees e ON e.employee_id = d.employee_id
+		LEFT JOIN employees de ON de.employee_id = d.delegate_id
+		WHERE d.employee_id = $1 OR d.delegate_id = $1
+		ORDER BY d.start_date DESC, d.delegation_id DESC
+	`, employeeID)
+	if err != nil {
+		log.Printf("Error querying delegations for employee %s: %v", employeeID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	delegations := []models.Delegation{}
+	for rows.Next() {
+		var d models.Delegation
+		var startDate, endDate time.Time
+		err := rows.Scan(
+			&d.DelegationID, &d.EmployeeID, &d.EmployeeName,
+			&d.DelegateID, &d.DelegateName,
+			&startDate, &endDate, &d.Reason, &d.Status, &d.CreatedAt,
+		)
+		if err != nil {
+			log.Printf("Error scanning delegation row: %v", err)
+			return nil, err
+		}
+		d.StartDate = startDate.Format(delegationDateLayout)
+		d.EndDate = endDate.Format(delegationDateLayout)
+		delegations = append(delegations, d)
+	}
+	return delegations, rows.Err()
+}
+
+func CancelDelegation(employeeID string, delegationID int) error {
+	result, err := database.DB.Exec(`
+		UPDATE approver_delegations SET status = 'Cancelled'
+		WHERE delegation_id = $1 AND employee_id = $2 AND status = 'Active'
+	`, delegationID, employeeID)
+	if err != nil {
+		log.Printf("Error cancelling delegation %d: %v", delegationID, err)
+		return errors.New("Failed to cancel delegation.")
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrDelegationNotFound
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"mantest/backend/internal/database"
+	"mantest/backend/internal/models"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrSequenceNotFound = errors.New("document sequence not found")
+	ErrInvalidSequence  = errors.New("invalid document sequence")
+)
+
+// maxDocNumberWidth matches manpower_requests.doc_number.
+const maxDocNumberWidth = 50
+
+var (
+	docTypePattern  = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,49}$`)
+	sequenceToken   = regexp.MustCompile(`\{([A-Z]+)(?::(\d+))?\}`)
+	sequenceLiteral = regexp.MustCompile(`^[A-Za-z0-9/_.-]*$`)
+)
+
+// sequenceDateTokens is the width each date token renders to.
+var sequenceDateTokens = map[string]int{"YYYY": 4, "YY": 2, "MM": 2, "DD": 2}
+
+// sequencePeriodTokens lists, per reset period, the date parts a pattern must
+// show so that a restarted running number cannot repeat an earlier one.
+var sequencePeriodTokens = map[string][]string{
+	models.SequenceResetNever:   nil,
+	models.SequenceResetYearly:  {"year"},
+	models.SequenceResetMonthly: {"year", "MM"},
+	models.SequenceResetDaily:   {"year", "MM", "DD"},
+}
+
+func validateSequencePattern(pattern, resetPeriod string) error {
+	required, ok := sequencePeriodTokens[resetPeriod]
+	if !ok {
+		return fmt.Errorf("unknown reset period %q", resetPeriod)
+	}
+
+	for _, literal := range sequenceToken.Split(pattern, -1) {
+		if !sequenceLiteral.MatchString(literal) {
+			return fmt.Errorf("pattern text %q may only contain letters, digits and - _ . /", literal)
+		}
+	}
+
+	width := len(sequenceToken.ReplaceAllString(pattern, ""))
+	seqCount := 0
+	present := map[string]bool{}
+	for _, match := range sequenceToken.FindAllStringSubmatch(pattern, -1) {
+		token, arg := match[1], match[2]
+		if token == "SEQ" {
+			digits, err := strconv.Atoi(arg)
+			if err != nil || digits < 1 || digits > 9 {
+				return errors.New("{SEQ:n} needs a width between 1 and 9")
+			}
+			seqCount++
+			width += digits
+			continue
+		}
+		tokenWidth, ok := sequenceDateTokens[token]
+		if !ok || arg != "" {
+			return fmt.Errorf("unknown pattern token %s", match[0])
+		}
+		if token == "YYYY" || token == "YY" {
+			token = "year"
+		}
+		present[token] = true
+		width += tokenWidth
+	}
+	if seqCount != 1 {
+		return errors.New("pattern must contain exactly one {SEQ:n} token")
+	}
+	for _, token := range required {
+		if !present[token] {
+			return fmt.Errorf("a %s reset needs the pattern to include the %s", strings.ToLower(resetPeriod), token)
+		}
+	}
+	if width > maxDocNumberWidth {
+		return fmt.Errorf("pattern renders numbers longer than %d characters", maxDocNumberWidth)
+	}
+	return nil
+}
+
+// sequencePeriodKey names the period a running number belongs to.
+func sequencePeriodKey(resetPeriod string, at time.Time) string {
+	switch resetPeriod {
+	case models.SequenceResetYearly:
+		return at.Format("2006")
+	case models.SequenceResetMonthly:
+		return at.Format("2006-01")
+	case models.SequenceResetDaily:
+		return at.Format("2006-01-02")
+	}
+	return ""
+}
+
+func formatDocumentNumber(pattern string, at time.Time, value int) string {
+	return sequenceToken.ReplaceAllStringFunc(pattern, func(token string) string {
+		match := sequenceToken.FindStringSubmatch(token)
+		switch match[1] {
+		case "YYYY":
+			return at.Format("2006")
+		case "YY":
+			return at.Format("06")
+		case "MM":
+			return at.Format("01")
+		case "DD":
+			return at.Format("02")
+		case "SEQ":
+			digits, _ := strconv.Atoi(match[2])
+			return fmt.Sprintf("%0*d", digits, value)
+		}
+		return token
+	})
+}
+
+// NextDocumentNumber takes the next running number for docType in the
+// current period and renders it. The counter row stays locked until tx ends,
+// so concurrent submissions queue for it and a rolled back submission gives
+// its number back.
+func NextDocumentNumber(tx *sql.Tx, docType string) (string, error) {
+	var pattern, resetPeriod string
+	err := tx.QueryRow(`
+		SELECT pattern, reset_period FROM document_sequences WHERE doc_type = $1
+	`, docType).Scan(&pattern, &resetPeriod)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrSequenceNotFound
+		}
+		return "", err
+	}
+
+	now := time.Now().In(businessLocation)
+	var value int
+	err = tx.QueryRow(`
+		INSERT INTO document_sequence_counters (doc_type, period_key, last_value)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (doc_type, period_key)
+		DO UPDATE SET last_value = document_sequence_counters.last_value + 1
+		RETURNING last_value
+	`, docType, sequencePeriodKey(resetPeriod, now)).Scan(&value)
+	if err != nil {
+		log.Printf("Error advancing document sequence %s: %v", docType, err)
+		return "", err
+	}
+	return formatDocumentNumber(pattern, now, value), nil
+}
+
+func GetDocumentSequences() ([]models.DocumentSequence, error) {
+	rows, err := database.DB.Query(`
+		SELECT doc_type, pattern, reset_period, updated_at
+		FROM document_sequences
+		ORDER BY doc_type ASC
+	`)
+	if err != nil {
+		log.Printf("Error querying document sequences: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	sequences := []models.DocumentSequence{}
+	for rows.Next() {
+		var seq models.DocumentSequence
+		if err := rows.Scan(&seq.DocType, &seq.Pattern, &seq.ResetPeriod, &seq.UpdatedAt); err != nil {
+			log.Printf("Error scanning document sequence row: %v", err)
+			return nil, err
+		}
+		sequences = append(sequences, seq)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().In(businessLocation)
+	for i := range sequences {
+		seq := &sequences[i]
+		err := database.DB.QueryRow(`
+			SELECT COALESCE(MAX(last_value), 0) FROM document_sequence_counters
+			WHERE doc_type = $1 AND period_key = $2
+		`, seq.DocType, sequencePeriodKey(seq.ResetPeriod, now)).Scan(&seq.LastValue)
+		if err != nil {
+			log.Printf("Error querying counter for document sequence %s: %v", seq.DocType, err)
+			return nil, err
+		}
+		seq.NextNumber = formatDocumentNumber(seq.Pattern, now, seq.LastValue+1)
+	}
+	return sequences, nil
+}
+
+// SaveDocumentSequence creates or replaces the numbering scheme of a
+// document type. Running numbers already issued are kept.
+func SaveDocumentSequence(docType string, input *models.DocumentSequenceInput) error {
+	if !docTypePattern.MatchString(docType) {
+		return fmt.Errorf("%w: document type must be upper-case letters, digits and underscores", ErrInvalidSequence)
+	}
+	if err := validateSequencePattern(input.Pattern, input.ResetPeriod); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSequence, err)
+	}
+
+	// Counters are kept per period, so a new reset period starts counting
+	// again from one. Under an unchanged pattern that would repeat numbers
+	// already issued.
+	var periodChanged bool
+	err := database.DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM document_sequences s
+			JOIN document_sequence_counters c ON c.doc_type = s.doc_type
+			WHERE s.doc_type = $1 AND s.pattern = $2 AND s.reset_period <> $3
+		)
+	`, docType, input.Pattern, input.ResetPeriod).Scan(&periodChanged)
+	if err != nil {
+		log.Printf("Error checking document sequence %s: %v", docType, err)
+		return errors.New("failed to save document sequence")
+	}
+	if periodChanged {
+		return fmt.Errorf("%w: changing the reset period needs a new pattern, or numbers already issued would be repeated", ErrInvalidSequence)
+	}
+
+	_, err = database.DB.Exec(`
+		INSERT INTO document_sequences (doc_type, pattern, reset_period)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (doc_type)
+		DO UPDATE SET pattern = EXCLUDED.pattern, reset_period = EXCLUDED.reset_period, updated_at = CURRENT_TIMESTAMP
+	`, docType, input.Pattern, input.ResetPeriod)
+	if err != nil {
+		log.Printf("Error saving document sequence %s: %v", docType, err)
+		return errors.New("failed to save document sequence")
+	}
+	log.Printf("Document sequence %s set to %s (reset %s)", docType, input.Pattern, input.ResetPeriod)
+	return nil
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"mantest/backend/internal/models"
+	"testing"
+	"time"
+)
+
+func TestValidateSequencePattern(t *testing.T) {
+	tests := []struct {
+		name        string
+		pattern     string
+		resetPeriod string
+		ok          bool
+	}{
+		{"monthly with year and month", "PQ{YY}{MM}{SEQ:4}", models.SequenceResetMonthly, true},
+		{"yearly with four-digit year", "REQ-{YYYY}-{SEQ:5}", models.SequenceResetYearly, true},
+		{"daily with full date", "{YYYY}/{MM}/{DD}/{SEQ:3}", models.SequenceResetDaily, true},
+		{"never without date", "MP{SEQ:9}", models.SequenceResetNever, true},
+		{"unknown reset period", "PQ{SEQ:4}", "WEEKLY", false},
+		{"no running number", "PQ{YY}{MM}", models.SequenceResetMonthly, false},
+		{"two running numbers", "{SEQ:2}{YY}{MM}{SEQ:2}", models.SequenceResetMonthly, false},
+		{"running number too narrow", "PQ{YY}{MM}{SEQ:0}", models.SequenceResetMonthly, false},
+		{"running number too wide", "PQ{YY}{MM}{SEQ:10}", models.SequenceResetMonthly, false},
+		{"running number without width", "PQ{YY}{MM}{SEQ}", models.SequenceResetMonthly, false},
+		{"unknown token", "PQ{YY}{MM}{HH}{SEQ:4}", models.SequenceResetMonthly, false},
+		{"date token with width", "PQ{YY:2}{MM}{SEQ:4}", models.SequenceResetMonthly, false},
+		{"monthly without month", "PQ{YY}{SEQ:4}", models.SequenceResetMonthly, false},
+		{"yearly without year", "PQ{MM}{SEQ:4}", models.SequenceResetYearly, false},
+		{"daily without day", "PQ{YY}{MM}{SEQ:4}", models.SequenceResetDaily, false},
+		{"disallowed literal", "PQ {YY}{MM}{SEQ:4}", models.SequenceResetMonthly, false},
+		{"too long", "ABCDEFGHIJKLMNOPQRSTUVWXYZABCDEFGHIJKLMNOP{YYYY}{MM}{SEQ:4}", models.SequenceResetMonthly, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSequencePattern(tt.pattern, tt.resetPeriod)
+			if (err == nil) != tt.ok {
+				t.Errorf("validateSequencePattern(%q, %q) = %v, want ok=%v", tt.pattern, tt.resetPeriod, err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestFormatDocumentNumber(t *testing.T) {
+	at := time.Date(2026, time.March, 7, 10, 0, 0, 0, businessLocation)
+	tests := []struct {
+		pattern string
+		value   int
+		want    string
+	}{
+		{"PQ{YY}{MM}{SEQ:4}", 12, "PQ26030012"},
+		{"REQ-{YYYY}/{MM}/{DD}-{SEQ:3}", 7, "REQ-2026/03/07-007"},
+		{"MP{SEQ:2}", 123, "MP123"},
+	}
+	for _, tt := range tests {
+		if got := formatDocumentNumber(tt.pattern, at, tt.value); got != tt.want {
+			t.Errorf("formatDocumentNumber(%q, %d) = %q, want %q", tt.pattern, tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,354 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"mantest/backend/internal/database"
+	"mantest/backend/internal/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrDraftNotFound = errors.New("draft not found")
+
+// DraftIncompleteError lists the form fields a draft still needs before it
+// can be submitted.
+type DraftIncompleteError struct {
+	Missing []string
+}
+
+func (e *DraftIncompleteError) Error() string {
+	return fmt.Sprintf("draft is incomplete: missing %s", strings.Join(e.Missing, ", "))
+}
+
+func nullableInt(v int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(v), Valid: v != 0}
+}
+
+// lockOwnDraft locks a request that employeeID raised and that is still a
+// draft. Anything else is reported as not found so drafts stay private.
+func lockOwnDraft(tx *sql.Tx, draftID int, employeeID string) error {
+	var lifecycle string
+	err := tx.QueryRow(`
+		SELECT lifecycle_status FROM manpower_requests
+		WHERE request_id = $1 AND employee_id = $2
+		FOR UPDATE
+	`, draftID, employeeID).Scan(&lifecycle)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && lifecycle != models.RequestDraft) {
+		return ErrDraftNotFound
+	}
+	return err
+}
+
+// SaveDraft stores a partially filled request form under the requester. A
+// zero draftID creates a new draft; otherwise the existing draft is replaced.
+// Empty selections are kept as NULL and are only enforced on submit.
+func SaveDraft(draftID int, record *models.ManpowerRequestRecord) (int, error) {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var docDate sql.NullTime
+	if !record.DocDate.IsZero() {
+		docDate = sql.NullTime{Time: record.DocDate, Valid: true}
+	}
+	if record.Headcount <= 0 {
+		record.Headcount = 1
+	}
+
+	args := []interface{}{
+		docDate,
+		nullableInt(record.DeptID),
+		nullableInt(record.PosID),
+		nullableInt(record.EmploymentTypeID),
+		nullableInt(record.ContractTypeID),
+		nullableInt(record.ReasonID),
+		record.RequiredPositionCode,
+		record.RequiredPositionName,
+		nullableInt(record.MinAge),
+		nullableInt(record.MaxAge),
+		nullableInt(record.GenderID),
+		nullableInt(record.NationalityID),
+		nullableInt(record.ExperienceID),
+		nullableInt(record.EducationLevelID),
+		record.SpecialQualifications,
+		record.Headcount,
+		record.EmployeeID,
+	}
+
+	if draftID == 0 {
+		err = tx.QueryRow(`
+			INSERT INTO manpower_requests (
+				doc_date, requesting_dept_id, requesting_pos_id,
+				employment_type_id, contract_type_id, reason_id,
+				required_position_code, required_position_name, min_age, max_age,
+				gender_id, nationality_id, experience_id, education_level_id,
+				special_qualifications, headcount, employee_id, lifecycle_status, current_status
+			)
+			VALUES (COALESCE($1, CURRENT_DATE), $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+			RETURNING request_id
+		`, append(args, models.RequestDraft, models.RequestStatusDraft)...).Scan(&draftID)
+		if err != nil {
+			log.Printf("SQL INSERT Error: %v", err)
+			return 0, errors.New("Failed to save draft.")
+		}
+	} else {
+		if err := lockOwnDraft(tx, draftID, record.EmployeeID); err != nil {
+			return 0, err
+		}
+		_, err = tx.Exec(`
+			UPDATE manpower_requests SET
+				doc_date = COALESCE($1, doc_date), requesting_dept_id = $2, requesting_pos_id = $3,
+				employment_type_id = $4, contract_type_id = $5, reason_id = $6,
+				required_position_code = $7, required_position_name = $8, min_age = $9, max_age = $10,
+				gender_id = $11, nationality_id = $12, experience_id = $13, education_level_id = $14,
+				special_qualifications = $15, headcount = $16, updated_at = CURRENT_TIMESTAMP
+			WHERE request_id = $18 AND employee_id = $17
+		`, append(args, draftID)...)
+		if err != nil {
+			log.Printf("SQL UPDATE Error: %v", err)
+			return 0, errors.New("Failed to save draft.")
+		}
+	}
+
+	if err := saveRequestLines(tx, draftID, record.Lines); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return draftID, nil
+}
+
+// selectionIDColumns are the master-data references of a request, in the
+// order selectionIDs holds them.
+const selectionIDColumns = `r.requesting_dept_id, r.employment_type_id, r.contract_type_id, r.reason_id,
+	r.requesting_pos_id, r.gender_id, r.nationality_id, r.experience_id, r.education_level_id`
+
+type selectionIDs [9]sql.NullInt64
+
+func (ids *selectionIDs) targets() []interface{} {
+	targets := make([]interface{}, len(ids))
+	for i := range ids {
+		targets[i] = &ids[i]
+	}
+	return targets
+}
+
+func (ids *selectionIDs) assign(dests ...**int) {
+	for i, dest := range dests {
+		*dest = nullIntPtr(ids[i])
+	}
+}
+
+const draftSelect = `
+	SELECT r.request_id, r.doc_date,
+	       COALESCE(d.dept_name, ''), COALESCE(et.et_name, ''), COALESCE(ct.ct_name, ''), COALESCE(rr.rr_name, ''),
+	       r.required_position_code, r.required_position_name, r.min_age, r.max_age,
+	       COALESCE(g.gender_name, ''), COALESCE(n.nat_name, ''), COALESCE(x.exp_name, ''), COALESCE(ed.edu_name, ''),
+	       COALESCE(r.special_qualifications, ''), r.headcount, r.created_at, r.updated_at,
+	       ` + selectionIDColumns + `
+	FROM manpower_requests r
+	LEFT JOIN departments d ON d.dept_id = r.requesting_dept_id
+	LEFT JOIN employment_types et ON et.et_id = r.employment_type_id
+	LEFT JOIN contract_types ct ON ct.ct_id = r.contract_type_id
+	LEFT JOIN request_reasons rr ON rr.rr_id = r.reason_id
+	LEFT JOIN genders g ON g.gender_id = r.gender_id
+	LEFT JOIN nationalities n ON n.nat_id = r.nationality_id
+	LEFT JOIN experiences x ON x.exp_id = r.experience_id
+	LEFT JOIN education_levels ed ON ed.edu_id = r.education_level_id
+`
+
+func scanDraft(scanner interface{ Scan(...interface{}) error }) (*models.ManpowerRequestDraft, error) {
+	var d models.ManpowerRequestDraft
+	var docDate time.Time
+	var minAge, maxAge sql.NullInt64
+	var ids selectionIDs
+	err := scanner.Scan(append([]interface{}{
+		&d.DraftID, &docDate,
+		&d.Department, &d.EmploymentType, &d.ContractType, &d.RequestReason,
+		&d.PositionId, &d.PositionRequire, &minAge, &maxAge,
+		&d.Gender, &d.Nationality, &d.Experience, &d.EducationLevel,
+		&d.SpecialQualifications, &d.Headcount, &d.CreatedAt, &d.UpdatedAt,
+	}, ids.targets()...)...)
+	if err != nil {
+		return nil, err
+	}
+	ids.assign(
+		&d.DepartmentID, &d.EmploymentTypeID, &d.ContractTypeID, &d.RequestReasonID,
+		&d.PositionRequireID, &d.GenderID, &d.NationalityID, &d.ExperienceID, &d.EducationLevelID,
+	)
+	d.DocumentDate = docDate.Format("02/01/2006")
+	if minAge.Valid {
+		d.AgeFrom = strconv.FormatInt(minAge.Int64, 10)
+	}
+	if maxAge.Valid {
+		d.AgeTo = strconv.FormatInt(maxAge.Int64, 10)
+	}
+	return &d, nil
+}
+
+func GetDrafts(employeeID string) ([]models.ManpowerRequestDraft, error) {
+	rows, err := database.DB.Query(draftSelect+`
+		WHERE r.employee_id = $1 AND r.lifecycle_status = $2
+		ORDER BY r.updated_at DESC
+	`, employeeID, models.RequestDraft)
+	if err != nil {
+		log.Printf("Error querying drafts for employee %s: %v", employeeID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	drafts := []models.ManpowerRequestDraft{}
+	for rows.Next() {
+		draft, err := scanDraft(rows)
+		if err != nil {
+			log.Printf("Error scanning draft row: %v", err)
+			return nil, err
+		}
+		drafts = append(drafts, *draft)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return drafts, attachDraftLines(drafts)
+}
+
+func attachDraftLines(drafts []models.ManpowerRequestDraft) error {
+	ids := make([]int, len(drafts))
+	for i := range drafts {
+		ids[i] = drafts[i].DraftID
+	}
+	lines, err := loadRequestLines(ids)
+	if err != nil {
+		return err
+	}
+	for i := range drafts {
+		drafts[i].Lines = lines[drafts[i].DraftID]
+	}
+	return nil
+}
+
+func GetDraft(draftID int, employeeID string) (*models.ManpowerRequestDraft, error) {
+	row := database.DB.QueryRow(draftSelect+`
+		WHERE r.request_id = $1 AND r.employee_id = $2 AND r.lifecycle_status = $3
+	`, draftID, employeeID, models.RequestDraft)
+	draft, err := scanDraft(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrDraftNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	drafts := []models.ManpowerRequestDraft{*draft}
+	if err := attachDraftLines(drafts); err != nil {
+		return nil, err
+	}
+	return &drafts[0], nil
+}
+
+// draftRequiredFields maps the columns a submitted request must have to the
+// form field names reported back when they are missing. Positions are checked
+// on the lines instead.
+var draftRequiredFields = []struct {
+	column string
+	field  string
+}{
+	{"requesting_dept_id", "department"},
+	{"employment_type_id", "employmentType"},
+	{"contract_type_id", "contractType"},
+	{"reason_id", "requestReason"},
+	{"min_age", "ageFrom"},
+	{"max_age", "ageTo"},
+	{"gender_id", "gender"},
+	{"nationality_id", "nationality"},
+	{"experience_id", "experience"},
+	{"education_level_id", "educationLevel"},
+}
+
+// SubmitDraft checks that a draft is complete, gives it the next document
+// number and sends it into approval.
+func SubmitDraft(draftID int, employeeID string) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockOwnDraft(tx, draftID, employeeID); err != nil {
+		return err
+	}
+
+	checks := make([]string, len(draftRequiredFields))
+	for i, f := range draftRequiredFields {
+		checks[i] = f.column + " IS NULL"
+	}
+	missingFlags := make([]bool, len(draftRequiredFields))
+	dest := make([]interface{}, len(missingFlags))
+	for i := range missingFlags {
+		dest[i] = &missingFlags[i]
+	}
+	err = tx.QueryRow(`SELECT `+strings.Join(checks, ", ")+` FROM manpower_requests WHERE request_id = $1`, draftID).Scan(dest...)
+	if err != nil {
+		return err
+	}
+	var missing []string
+	for i, isMissing := range missingFlags {
+		if isMissing {
+			missing = append(missing, draftRequiredFields[i].field)
+		}
+	}
+	lineMissing, err := missingLineFields(tx, draftID)
+	if err != nil {
+		return err
+	}
+	missing = append(missing, lineMissing...)
+	if len(missing) > 0 {
+		return &DraftIncompleteError{Missing: missing}
+	}
+
+	docNumber, err := NextDocumentNumber(tx, models.DocTypeManpowerRequest)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`UPDATE manpower_requests SET doc_number = $1 WHERE request_id = $2`, docNumber, draftID)
+	if err != nil {
+		log.Printf("SQL UPDATE Error: %v", err)
+		if strings.Contains(err.Error(), "manpower_requests_doc_number_key") {
+			return ErrDuplicateDocNumber
+		}
+		return errors.New("Failed to submit draft.")
+	}
+
+	if err := submitRequest(tx, draftID, employeeID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DiscardDraft cancels a draft. The row is kept so the transition log stays
+// complete.
+func DiscardDraft(draftID int, employeeID string) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockOwnDraft(tx, draftID, employeeID); err != nil {
+		return err
+	}
+	if err := transitionRequest(tx, draftID, models.RequestCancelled, employeeID, ""); err != nil {
+		return err
+	}
+	if err := setRequestStatus(tx, draftID, models.RequestStatusCancelled); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
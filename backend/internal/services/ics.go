@@ -0,0 +1,159 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxICSEventDays caps how many days a single event may span so a malformed
+// DTEND cannot flood the holiday table.
+const maxICSEventDays = 31
+
+const maxHolidayNameLength = 150
+
+type icsEvent struct {
+	summary string
+	start   time.Time
+	end     time.Time // exclusive, as in RFC 5545
+}
+
+func (e icsEvent) dates() []time.Time {
+	var dates []time.Time
+	for d := e.start; d.Before(e.end); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d)
+	}
+	return dates
+}
+
+// unfoldICSLines joins RFC 5545 folded lines, where a continuation line
+// starts with a space or tab.
+func unfoldICSLines(data []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func unescapeICSText(value string) string {
+	replacer := strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return strings.TrimSpace(replacer.Replace(value))
+}
+
+// parseICSDate accepts DATE values and DATE-TIME values; only the calendar
+// date is kept. UTC times and times with a known TZID are moved to the
+// business zone first, so an event at 17:00Z counts on the next Thai day.
+// Floating times and unknown zones keep their own date.
+func parseICSDate(value, params string) (time.Time, error) {
+	if len(value) == 8 {
+		return time.Parse("20060102", value)
+	}
+
+	loc, zoned := time.UTC, strings.HasSuffix(value, "Z")
+	if !zoned {
+		loc, zoned = icsLocation(params)
+	}
+	t, err := time.ParseInLocation("20060102T150405", strings.TrimSuffix(value, "Z"), loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	if zoned {
+		t = t.In(businessLocation)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+}
+
+// icsLocation returns the zone named by a TZID parameter, if there is one
+// the runtime knows.
+func icsLocation(params string) (*time.Location, bool) {
+	for _, param := range strings.Split(params, ";") {
+		key, tzid, ok := strings.Cut(param, "=")
+		if !ok || !strings.EqualFold(key, "TZID") {
+			continue
+		}
+		loc, err := time.LoadLocation(strings.Trim(tzid, `"`))
+		if err != nil {
+			return time.UTC, false
+		}
+		return loc, true
+	}
+	return time.UTC, false
+}
+
+func parseICSEvents(data []byte) ([]icsEvent, error) {
+	var events []icsEvent
+	var current *icsEvent
+	var hasEnd bool
+
+	for n, line := range unfoldICSLines(data) {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		// Split off parameters such as ";VALUE=DATE" or ";TZID=Asia/Bangkok".
+		name, params, _ := strings.Cut(name, ";")
+
+		switch strings.ToUpper(name) {
+		case "BEGIN":
+			if strings.EqualFold(value, "VEVENT") {
+				current = &icsEvent{}
+				hasEnd = false
+			}
+		case "END":
+			if !strings.EqualFold(value, "VEVENT") || current == nil {
+				continue
+			}
+			if current.start.IsZero() {
+				return nil, fmt.Errorf("line %d: event without DTSTART", n+1)
+			}
+			if !hasEnd || !current.end.After(current.start) {
+				current.end = current.start.AddDate(0, 0, 1)
+			}
+			if current.end.Sub(current.start) > maxICSEventDays*24*time.Hour {
+				return nil, fmt.Errorf("line %d: event %q spans more than %d days", n+1, current.summary, maxICSEventDays)
+			}
+			if current.summary == "" {
+				current.summary = "วันหยุด"
+			}
+			if runes := []rune(current.summary); len(runes) > maxHolidayNameLength {
+				current.summary = string(runes[:maxHolidayNameLength])
+			}
+			events = append(events, *current)
+			current = nil
+		case "DTSTART":
+			if current == nil {
+				continue
+			}
+			start, err := parseICSDate(value, params)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", n+1, err)
+			}
+			current.start = start
+		case "DTEND":
+			if current == nil {
+				continue
+			}
+			end, err := parseICSDate(value, params)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", n+1, err)
+			}
+			current.end = end
+			hasEnd = true
+		case "SUMMARY":
+			if current != nil {
+				current.summary = unescapeICSText(value)
+			}
+		}
+	}
+	return events, nil
+}
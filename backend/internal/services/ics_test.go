@@ -0,0 +1,117 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseICSDate(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		params  string
+		want    string
+		wantErr bool
+	}{
+		{"date", "20260413", "VALUE=DATE", "2026-04-13", false},
+		{"UTC time, same Thai day", "20260413T100000Z", "", "2026-04-13", false},
+		{"UTC time, next Thai day", "20260412T170000Z", "", "2026-04-13", false},
+		{"floating time", "20260413T230000", "", "2026-04-13", false},
+		{"zoned time", "20260412T230000", "TZID=UTC", "2026-04-13", false},
+		{"quoted zone", "20260412T230000", `TZID="UTC"`, "2026-04-13", false},
+		{"unknown zone keeps its date", "20260412T230000", "TZID=Nowhere/Special", "2026-04-12", false},
+		{"too short", "2026041", "", "", true},
+		{"not a date", "2026xx13", "", "", true},
+		{"bad time", "20260413T25", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseICSDate(tt.value, tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseICSDate(%q, %q) error = %v, wantErr %v", tt.value, tt.params, err, tt.wantErr)
+			}
+			if err == nil && got.Format(holidayDateLayout) != tt.want {
+				t.Errorf("parseICSDate(%q, %q) = %s, want %s", tt.value, tt.params, got.Format(holidayDateLayout), tt.want)
+			}
+		})
+	}
+}
+
+func icsCalendar(lines ...string) []byte {
+	return []byte("BEGIN:VCALENDAR\r\n" + strings.Join(lines, "\r\n") + "\r\nEND:VCALENDAR\r\n")
+}
+
+func TestParseICSEvents(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    map[string]string // date -> summary
+		wantErr bool
+	}{
+		{
+			name: "single day without end",
+			data: icsCalendar("BEGIN:VEVENT", "DTSTART;VALUE=DATE:20260101", "SUMMARY:วันขึ้นปีใหม่", "END:VEVENT"),
+			want: map[string]string{"2026-01-01": "วันขึ้นปีใหม่"},
+		},
+		{
+			name: "multi-day with exclusive end",
+			data: icsCalendar("BEGIN:VEVENT", "DTSTART;VALUE=DATE:20260413", "DTEND;VALUE=DATE:20260416", "SUMMARY:วันสงกรานต์", "END:VEVENT"),
+			want: map[string]string{"2026-04-13": "วันสงกรานต์", "2026-04-14": "วันสงกรานต์", "2026-04-15": "วันสงกรานต์"},
+		},
+		{
+			name: "folded and escaped summary",
+			data: icsCalendar("BEGIN:VEVENT", "DTSTART:20260501", "SUMMARY:Labour\\, May", " Day", "END:VEVENT"),
+			want: map[string]string{"2026-05-01": "Labour, MayDay"},
+		},
+		{
+			name: "missing summary",
+			data: icsCalendar("BEGIN:VEVENT", "DTSTART:20260501", "END:VEVENT"),
+			want: map[string]string{"2026-05-01": "วันหยุด"},
+		},
+		{
+			name: "UTC timed event",
+			data: icsCalendar("BEGIN:VEVENT", "DTSTART:20260412T170000Z", "DTEND:20260412T180000Z", "SUMMARY:Late", "END:VEVENT"),
+			want: map[string]string{"2026-04-13": "Late"},
+		},
+		{
+			name:    "missing start",
+			data:    icsCalendar("BEGIN:VEVENT", "SUMMARY:Nothing", "END:VEVENT"),
+			wantErr: true,
+		},
+		{
+			name:    "bad start",
+			data:    icsCalendar("BEGIN:VEVENT", "DTSTART:2026", "END:VEVENT"),
+			wantErr: true,
+		},
+		{
+			name:    "too long",
+			data:    icsCalendar("BEGIN:VEVENT", "DTSTART:20260101", "DTEND:20260301", "END:VEVENT"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := parseICSEvents(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseICSEvents() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got := map[string]string{}
+			for _, e := range events {
+				for _, d := range e.dates() {
+					got[d.Format(holidayDateLayout)] = e.summary
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseICSEvents() dates = %v, want %v", got, tt.want)
+			}
+			for date, summary := range tt.want {
+				if got[date] != summary {
+					t.Errorf("summary on %s = %q, want %q", date, got[date], summary)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,331 @@
+package services
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"mantest/backend/internal/models"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	defaultJWTKeyID  = "default"
+	defaultJWTExpiry = 15 * time.Minute
+
+	// minHMACSecretLength matches the 256-bit output of HS256.
+	minHMACSecretLength = 32
+)
+
+type jwtKey struct {
+	kid       string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+type jwtSettings struct {
+	issuer    string
+	audience  string
+	expiry    time.Duration
+	activeKID string
+	legacyKID string
+	keys      map[string]*jwtKey
+}
+
+var jwtConfig *jwtSettings
+
+// InitJWT loads token signing configuration from the environment:
+//
+//	JWT_ALGORITHM         HS256 (default), RS256 or EdDSA
+//	JWT_SECRET            HMAC secret registered under JWT_KEY_ID
+//	JWT_KEYS              extra HMAC keys as "kid:secret,kid:secret"
+//	JWT_PRIVATE_KEY_FILE  PEM private key used for RS256/EdDSA signing
+//	JWT_PUBLIC_KEYS       retired public keys as "kid:/path/key.pem,..."
+//	JWT_KEY_ID            kid of JWT_SECRET / JWT_PRIVATE_KEY_FILE
+//	JWT_ACTIVE_KID        kid used to sign new tokens (defaults to JWT_KEY_ID)
+//	JWT_ISSUER, JWT_AUDIENCE, JWT_EXPIRY
+func InitJWT() error {
+	settings := &jwtSettings{
+		issuer:   os.Getenv("JWT_ISSUER"),
+		audience: os.Getenv("JWT_AUDIENCE"),
+		expiry:   defaultJWTExpiry,
+		keys:     make(map[string]*jwtKey),
+	}
+
+	if value := os.Getenv("JWT_EXPIRY"); value != "" {
+		expiry, err := time.ParseDuration(value)
+		if err != nil || expiry <= 0 {
+			return fmt.Errorf("invalid JWT_EXPIRY %q", value)
+		}
+		settings.expiry = expiry
+	}
+
+	keyID := os.Getenv("JWT_KEY_ID")
+	if keyID == "" {
+		keyID = defaultJWTKeyID
+	}
+
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		if err := settings.addHMACKey(keyID, secret); err != nil {
+			return fmt.Errorf("JWT_SECRET: %w", err)
+		}
+	}
+	for _, entry := range splitKeyList(os.Getenv("JWT_KEYS")) {
+		kid, secret, ok := strings.Cut(entry, ":")
+		if !ok || kid == "" || secret == "" {
+			return fmt.Errorf("invalid JWT_KEYS entry %q", entry)
+		}
+		if err := settings.addHMACKey(kid, secret); err != nil {
+			return fmt.Errorf("JWT_KEYS entry %q: %w", kid, err)
+		}
+	}
+
+	algorithm := strings.ToUpper(os.Getenv("JWT_ALGORITHM"))
+	switch algorithm {
+	case "", "HS256":
+		if len(settings.keys) == 0 {
+			return errors.New("JWT_SECRET is required for HS256 signing")
+		}
+	case "RS256", "EDDSA":
+		path := os.Getenv("JWT_PRIVATE_KEY_FILE")
+		if path == "" {
+			return fmt.Errorf("JWT_PRIVATE_KEY_FILE is required for %s signing", algorithm)
+		}
+		key, err := loadPrivateKey(keyID, path)
+		if err != nil {
+			return err
+		}
+		expected := jwt.SigningMethod(jwt.SigningMethodRS256)
+		if algorithm == "EDDSA" {
+			expected = jwt.SigningMethodEdDSA
+		}
+		if key.method != expected {
+			return fmt.Errorf("JWT_PRIVATE_KEY_FILE does not contain a %s key", expected.Alg())
+		}
+		settings.keys[keyID] = key
+	default:
+		return fmt.Errorf("unsupported JWT_ALGORITHM %q", algorithm)
+	}
+
+	for _, entry := range splitKeyList(os.Getenv("JWT_PUBLIC_KEYS")) {
+		kid, path, ok := strings.Cut(entry, ":")
+		if !ok || kid == "" || path == "" {
+			return fmt.Errorf("invalid JWT_PUBLIC_KEYS entry %q", entry)
+		}
+		key, err := loadPublicKey(kid, path)
+		if err != nil {
+			return err
+		}
+		settings.keys[kid] = key
+	}
+
+	settings.legacyKID = keyID
+	settings.activeKID = os.Getenv("JWT_ACTIVE_KID")
+	if settings.activeKID == "" {
+		settings.activeKID = keyID
+	}
+	active, ok := settings.keys[settings.activeKID]
+	if !ok || active.signKey == nil {
+		return fmt.Errorf("no signing key configured for kid %q", settings.activeKID)
+	}
+
+	jwtConfig = settings
+	log.Printf("JWT signing configured: alg=%s kid=%s keys=%d expiry=%s", active.method.Alg(), settings.activeKID, len(settings.keys), settings.expiry)
+	return nil
+}
+
+func splitKeyList(value string) []string {
+	var entries []string
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func (s *jwtSettings) addHMACKey(kid, secret string) error {
+	if len(secret) < minHMACSecretLength {
+		return fmt.Errorf("secret must be at least %d bytes", minHMACSecretLength)
+	}
+	s.keys[kid] = &jwtKey{
+		kid:       kid,
+		method:    jwt.SigningMethodHS256,
+		signKey:   []byte(secret),
+		verifyKey: []byte(secret),
+	}
+	return nil
+}
+
+func readPEMBlock(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file %s: %w", path, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key file %s is not PEM encoded", path)
+	}
+	return block, nil
+}
+
+func loadPrivateKey(kid, path string) (*jwtKey, error) {
+	block, err := readPEMBlock(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed interface{}
+	if block.Type == "RSA PRIVATE KEY" {
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	} else {
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key %s: %w", path, err)
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		return &jwtKey{kid: kid, method: jwt.SigningMethodRS256, signKey: key, verifyKey: &key.PublicKey}, nil
+	case ed25519.PrivateKey:
+		return &jwtKey{kid: kid, method: jwt.SigningMethodEdDSA, signKey: key, verifyKey: key.Public()}, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type in %s", path)
+	}
+}
+
+func loadPublicKey(kid, path string) (*jwtKey, error) {
+	block, err := readPEMBlock(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed interface{}
+	if block.Type == "RSA PUBLIC KEY" {
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	} else {
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key %s: %w", path, err)
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PublicKey:
+		return &jwtKey{kid: kid, method: jwt.SigningMethodRS256, verifyKey: key}, nil
+	case ed25519.PublicKey:
+		return &jwtKey{kid: kid, method: jwt.SigningMethodEdDSA, verifyKey: key}, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type in %s", path)
+	}
+}
+
+func tokenExpiry() time.Duration {
+	return jwtConfig.expiry
+}
+
+func signClaims(claims jwt.Claims) (string, error) {
+	settings := jwtConfig
+	key := settings.keys[settings.activeKID]
+
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.kid
+	return token.SignedString(key.signKey)
+}
+
+func registeredClaims(subject string, ttl time.Duration) jwt.RegisteredClaims {
+	settings := jwtConfig
+	now := time.Now()
+
+	claims := jwt.RegisteredClaims{
+		Subject:   subject,
+		Issuer:    settings.issuer,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+	}
+	if settings.audience != "" {
+		claims.Audience = jwt.ClaimStrings{settings.audience}
+	}
+	return claims
+}
+
+func parseSignedClaims(tokenString string, claims jwt.Claims) error {
+	settings := jwtConfig
+
+	options := []jwt.ParserOption{jwt.WithExpirationRequired()}
+	if settings.issuer != "" {
+		options = append(options, jwt.WithIssuer(settings.issuer))
+	}
+	if settings.audience != "" {
+		options = append(options, jwt.WithAudience(settings.audience))
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		if kid == "" {
+			kid = settings.legacyKID
+		}
+		key, ok := settings.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		if t.Method.Alg() != key.method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %s for key %q", t.Method.Alg(), kid)
+		}
+		return key.verifyKey, nil
+	}, options...)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return ErrTokenExpired
+		}
+		return ErrTokenInvalid
+	}
+	if !token.Valid {
+		return ErrTokenInvalid
+	}
+	return nil
+}
+
+func base64URLUint(value *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(value.Bytes())
+}
+
+func GetJWKS() models.JWKSet {
+	settings := jwtConfig
+	set := models.JWKSet{Keys: []models.JWK{}}
+
+	for _, key := range settings.keys {
+		switch pub := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, models.JWK{
+				KeyType:   "RSA",
+				KeyID:     key.kid,
+				Use:       "sig",
+				Algorithm: key.method.Alg(),
+				N:         base64URLUint(pub.N),
+				E:         base64URLUint(big.NewInt(int64(pub.E))),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, models.JWK{
+				KeyType:   "OKP",
+				KeyID:     key.kid,
+				Use:       "sig",
+				Algorithm: key.method.Alg(),
+				Curve:     "Ed25519",
+				X:         base64.RawURLEncoding.EncodeToString(pub),
+			})
+		}
+	}
+	return set
+}
@@ -0,0 +1,264 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"mantest/backend/internal/database"
+	"mantest/backend/internal/models"
+	"strings"
+	"time"
+)
+
+// Accounts are locked after repeated failures. Addresses are only slowed
+// down, never locked: behind a proxy that is not trusted every user shares
+// one address, and a lockout there would shut everyone out.
+const (
+	accountMaxFailures   = 5
+	loginFailureWindow   = 15 * time.Minute
+	loginLockoutDuration = 15 * time.Minute
+	loginDelayThreshold  = 3
+	ipDelayThreshold     = 20
+	loginMaxDelay        = 30 * time.Second
+)
+
+const (
+	LoginFailureUnknownAccount = "unknown_account"
+	LoginFailureBadPassword    = "bad_password"
+	LoginFailureResetRequired  = "password_reset_required"
+	LoginFailureThrottled      = "throttled"
+	LoginFailureLockedOut      = "locked_out"
+	LoginFailureError          = "error"
+)
+
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *LoginThrottledError) Error() string {
+	if e.Locked {
+		return fmt.Sprintf("too many failed login attempts: locked for %s", e.RetryAfter.Round(time.Second))
+	}
+	return fmt.Sprintf("too many failed login attempts: retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+func accountThrottleKey(email string) string {
+	return "account:" + strings.ToLower(email)
+}
+
+func ipThrottleKey(ip string) string {
+	return "ip:" + ip
+}
+
+func progressiveDelay(failures, threshold int) time.Duration {
+	if failures < threshold {
+		return 0
+	}
+	delay := time.Second << uint(failures-threshold)
+	if delay > loginMaxDelay || delay <= 0 {
+		return loginMaxDelay
+	}
+	return delay
+}
+
+func checkThrottle(key string, delayThreshold int) (*LoginThrottledError, error) {
+	var failures int
+	var lastFailure, lockedUntil sql.NullTime
+	err := database.DB.QueryRow(`
+		SELECT failure_count, last_failure_at, locked_until
+		FROM login_throttle
+		WHERE throttle_key = $1
+	`, key).Scan(&failures, &lastFailure, &lockedUntil)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if lockedUntil.Valid {
+		if now.Before(lockedUntil.Time) {
+			return &LoginThrottledError{RetryAfter: lockedUntil.Time.Sub(now), Locked: true}, nil
+		}
+		return nil, nil
+	}
+	if !lastFailure.Valid || now.Sub(lastFailure.Time) > loginFailureWindow {
+		return nil, nil
+	}
+
+	nextAttempt := lastFailure.Time.Add(progressiveDelay(failures, delayThreshold))
+	if now.Before(nextAttempt) {
+		return &LoginThrottledError{RetryAfter: nextAttempt.Sub(now)}, nil
+	}
+	return nil, nil
+}
+
+// registerFailure counts a failed attempt against key and, once maxFailures
+// is reached, locks it. A maxFailures of 0 never locks.
+func registerFailure(key string, maxFailures int) error {
+	var failures int
+	err := database.DB.QueryRow(`
+		INSERT INTO login_throttle (throttle_key, failure_count, last_failure_at)
+		VALUES ($1, 1, CURRENT_TIMESTAMP)
+		ON CONFLICT (throttle_key) DO UPDATE SET
+			failure_count = CASE
+				WHEN login_throttle.last_failure_at < CURRENT_TIMESTAMP - ($2 * INTERVAL '1 second')
+				  OR login_throttle.locked_until < CURRENT_TIMESTAMP THEN 1
+				ELSE login_throttle.failure_count + 1
+			END,
+			last_failure_at = CURRENT_TIMESTAMP,
+			locked_until = NULL
+		RETURNING failure_count
+	`, key, int(loginFailureWindow.Seconds())).Scan(&failures)
+	if err != nil {
+		return err
+	}
+
+	if maxFailures > 0 && failures >= maxFailures {
+		_, err = database.DB.Exec(`
+			UPDATE login_throttle
+			SET locked_until = CURRENT_TIMESTAMP + ($2 * INTERVAL '1 second')
+			WHERE throttle_key = $1
+		`, key, int(loginLockoutDuration.Seconds()))
+		if err != nil {
+			return err
+		}
+		log.Printf("Login throttle %s locked after %d failures", key, failures)
+	}
+	return nil
+}
+
+func checkLoginAllowed(email, ip string) (*LoginThrottledError, error) {
+	throttled, err := checkThrottle(accountThrottleKey(email), loginDelayThreshold)
+	if err != nil || throttled != nil {
+		return throttled, err
+	}
+	if ip == "" {
+		return nil, nil
+	}
+	return checkThrottle(ipThrottleKey(ip), ipDelayThreshold)
+}
+
+func registerLoginFailure(email, ip string) {
+	if err := registerFailure(accountThrottleKey(email), accountMaxFailures); err != nil {
+		log.Printf("Failed to record login failure for %s: %v", email, err)
+	}
+	if ip != "" {
+		if err := registerFailure(ipThrottleKey(ip), 0); err != nil {
+			log.Printf("Failed to record login failure for IP %s: %v", ip, err)
+		}
+	}
+}
+
+func clearLoginFailures(email string) {
+	if _, err := database.DB.Exec(`DELETE FROM login_throttle WHERE throttle_key = $1`, accountThrottleKey(email)); err != nil {
+		log.Printf("Failed to clear login failures for %s: %v", email, err)
+	}
+}
+
+func recordLoginEvent(email, employeeID string, success bool, reason string, client models.ClientInfo) {
+	var sqlEmployeeID, sqlReason sql.NullString
+	if employeeID != "" {
+		sqlEmployeeID = sql.NullString{String: employeeID, Valid: true}
+	}
+	if reason != "" {
+		sqlReason = sql.NullString{String: reason, Valid: true}
+	}
+
+	_, err := database.DB.Exec(`
+		INSERT INTO login_events (email, employee_id, success, failure_reason, ip_address, user_agent)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, email, sqlEmployeeID, success, sqlReason, client.IPAddress, client.UserAgent)
+	if err != nil {
+		log.Printf("Failed to record login event for %s: %v", email, err)
+	}
+}
+
+func UnlockEmployeeAccount(employeeID string) error {
+	var email string
+	err := database.DB.QueryRow(`SELECT email FROM employees WHERE employee_id = $1`, employeeID).Scan(&email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrEmployeeNotFound
+		}
+		return err
+	}
+
+	if _, err := database.DB.Exec(`DELETE FROM login_throttle WHERE throttle_key = $1`, accountThrottleKey(email)); err != nil {
+		log.Printf("Failed to unlock account %s: %v", employeeID, err)
+		return errors.New("Failed to unlock account.")
+	}
+
+	log.Printf("Login lockout cleared for employee %s", employeeID)
+	return nil
+}
+
+func GetLoginEvents(filter models.LoginEventFilter) ([]models.LoginEvent, error) {
+	var conditions []string
+	var args []interface{}
+	addCondition := func(clause string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(clause, len(args)))
+	}
+
+	if filter.Email != "" {
+		addCondition("email = $%d", strings.ToLower(filter.Email))
+	}
+	if filter.EmployeeID != "" {
+		addCondition("employee_id = $%d", filter.EmployeeID)
+	}
+	if filter.IPAddress != "" {
+		addCondition("ip_address = $%d", filter.IPAddress)
+	}
+	if filter.Success != nil {
+		addCondition("success = $%d", *filter.Success)
+	}
+	if filter.From != nil {
+		addCondition("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("created_at < $%d", *filter.To)
+	}
+
+	query := `
+        SELECT event_id, email, COALESCE(employee_id, ''), success, COALESCE(failure_reason, ''),
+               COALESCE(ip_address, ''), COALESCE(user_agent, ''), created_at
+        FROM login_events
+    `
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	query += fmt.Sprintf(" ORDER BY created_at DESC, event_id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		log.Printf("Error querying login events: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.LoginEvent{}
+	for rows.Next() {
+		var event models.LoginEvent
+		err := rows.Scan(
+			&event.EventID,
+			&event.Email,
+			&event.EmployeeID,
+			&event.Success,
+			&event.FailureReason,
+			&event.IPAddress,
+			&event.UserAgent,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			log.Printf("Error scanning login event row: %v", err)
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
@@ -1,71 +1,47 @@
 package services
 
 import (
-	"database/sql"
 	"errors"
 	"fmt"
 	"log"
 	"mantest/backend/internal/database"
 	"mantest/backend/internal/models"
+	"mantest/backend/internal/validation"
+	"strconv"
+	"strings"
 )
 
-func GetMasterDataByType(tableName string) ([]models.MasterDataItem, error) {
-	var idCol, nameCol, tableAlias string
+type masterDataTable struct {
+	idCol   string
+	nameCol string
+	table   string
+}
 
-	switch tableName {
-	case "department":
-		idCol = "dept_id"
-		nameCol = "dept_name"
-		tableAlias = "departments"
-	case "position":
-		idCol = "pos_id"
-		nameCol = "pos_name"
-		tableAlias = "positions"
-	case "section":
-		idCol = "section_id"
-		nameCol = "section_name"
-		tableAlias = "sections"
-	case "employment_type":
-		idCol = "et_id"
-		nameCol = "et_name"
-		tableAlias = "employment_types"
-	case "contract_type":
-		idCol = "ct_id"
-		nameCol = "ct_name"
-		tableAlias = "contract_types"
-	case "request_reason":
-		idCol = "rr_id"
-		nameCol = "rr_name"
-		tableAlias = "request_reasons"
-	case "gender":
-		idCol = "gender_id"
-		nameCol = "gender_name"
-		tableAlias = "genders"
-	case "nationality":
-		idCol = "nat_id"
-		nameCol = "nat_name"
-		tableAlias = "nationalities"
-	case "experience":
-		idCol = "exp_id"
-		nameCol = "exp_name"
-		tableAlias = "experiences"
-	case "education_level":
-		idCol = "edu_id"
-		nameCol = "edu_name"
-		tableAlias = "education_levels"
-	case "role":
-		idCol = "role_id"
-		nameCol = "role_name"
-		tableAlias = "roles"
-	default:
+var masterDataTables = map[string]masterDataTable{
+	"department":      {"dept_id", "dept_name", "departments"},
+	"position":        {"pos_id", "pos_name", "positions"},
+	"section":         {"section_id", "section_name", "sections"},
+	"employment_type": {"et_id", "et_name", "employment_types"},
+	"contract_type":   {"ct_id", "ct_name", "contract_types"},
+	"request_reason":  {"rr_id", "rr_name", "request_reasons"},
+	"gender":          {"gender_id", "gender_name", "genders"},
+	"nationality":     {"nat_id", "nat_name", "nationalities"},
+	"experience":      {"exp_id", "exp_name", "experiences"},
+	"education_level": {"edu_id", "edu_name", "education_levels"},
+	"role":            {"role_id", "role_name", "roles"},
+}
+
+func GetMasterDataByType(tableName string) ([]models.MasterDataItem, error) {
+	t, ok := masterDataTables[tableName]
+	if !ok {
 		return nil, errors.New("invalid master data table name")
 	}
 
-	query := fmt.Sprintf("SELECT %s, %s FROM %s ORDER BY %s ASC", idCol, nameCol, tableAlias, idCol)
+	query := fmt.Sprintf("SELECT %s, %s FROM %s ORDER BY %s ASC", t.idCol, t.nameCol, t.table, t.idCol)
 
 	rows, err := database.DB.Query(query)
 	if err != nil {
-		log.Printf("Error querying %s: %v", tableAlias, err)
+		log.Printf("Error querying %s: %v", t.table, err)
 		return nil, err
 	}
 	defer rows.Close()
@@ -74,7 +50,7 @@ func GetMasterDataByType(tableName string) ([]models.MasterDataItem, error) {
 	for rows.Next() {
 		var item models.MasterDataItem
 		if err := rows.Scan(&item.ID, &item.Name); err != nil {
-			log.Printf("Error scanning %s row: %v", tableAlias, err)
+			log.Printf("Error scanning %s row: %v", t.table, err)
 			return nil, err
 		}
 		items = append(items, item)
@@ -82,69 +58,82 @@ func GetMasterDataByType(tableName string) ([]models.MasterDataItem, error) {
 	return items, rows.Err()
 }
 
-func GetIDByName(tableName, name string) (int, error) {
-	var idCol, nameCol, tableAlias string
+// MasterDataLookup is one master-data selection of a submission, given by ID
+// or by name. When both are set the ID wins, so renaming an entry does not
+// break clients that send IDs. Field is the payload field reported back when
+// the selection matches nothing.
+type MasterDataLookup struct {
+	Field string
+	Table string
+	ID    int
+	Name  string
+}
+
+func (l MasterDataLookup) empty() bool {
+	return l.ID == 0 && strings.TrimSpace(l.Name) == ""
+}
+
+// ResolveMasterData resolves every lookup in a single query. The entries come
+// back in the order of lookups, zero for an empty lookup, and every selection
+// that matches nothing is reported as a field error rather than stopping at
+// the first.
+func ResolveMasterData(lookups []MasterDataLookup) ([]models.MasterDataItem, []models.FieldError, error) {
+	items := make([]models.MasterDataItem, len(lookups))
+	var selects []string
+	var args []interface{}
+	for i, l := range lookups {
+		if l.empty() {
+			continue
+		}
+		t, ok := masterDataTables[l.Table]
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid lookup table name %q", l.Table)
+		}
+		condition := fmt.Sprintf("UPPER(%s) = UPPER($%d)", t.nameCol, len(args)+1)
+		args = append(args, l.Name)
+		if l.ID != 0 {
+			condition = fmt.Sprintf("%s = $%d", t.idCol, len(args))
+			args[len(args)-1] = l.ID
+		}
+		selects = append(selects, fmt.Sprintf(
+			"SELECT %d, %s, %s FROM %s WHERE %s", i, t.idCol, t.nameCol, t.table, condition,
+		))
+	}
+
+	found := make([]bool, len(lookups))
+	if len(selects) > 0 {
+		rows, err := database.DB.Query(strings.Join(selects, " UNION ALL "), args...)
+		if err != nil {
+			log.Printf("Database error resolving master data: %v", err)
+			return nil, nil, err
+		}
+		defer rows.Close()
 
-	switch tableName {
-	case "department":
-		idCol = "dept_id"
-		nameCol = "dept_name"
-		tableAlias = "departments"
-	case "position":
-		idCol = "pos_id"
-		nameCol = "pos_name"
-		tableAlias = "positions"
-	case "section":
-		idCol = "section_id"
-		nameCol = "section_name"
-		tableAlias = "sections"
-	case "employment_type":
-		idCol = "et_id"
-		nameCol = "et_name"
-		tableAlias = "employment_types"
-	case "contract_type":
-		idCol = "ct_id"
-		nameCol = "ct_name"
-		tableAlias = "contract_types"
-	case "request_reason":
-		idCol = "rr_id"
-		nameCol = "rr_name"
-		tableAlias = "request_reasons"
-	case "gender":
-		idCol = "gender_id"
-		nameCol = "gender_name"
-		tableAlias = "genders"
-	case "nationality":
-		idCol = "nat_id"
-		nameCol = "nat_name"
-		tableAlias = "nationalities"
-	case "experience":
-		idCol = "exp_id"
-		nameCol = "exp_name"
-		tableAlias = "experiences"
-	case "education_level":
-		idCol = "edu_id"
-		nameCol = "edu_name"
-		tableAlias = "education_levels"
-	case "role":
-		idCol = "role_id"
-		nameCol = "role_name"
-		tableAlias = "roles"
-	default:
-		return 0, errors.New("invalid lookup table name")
+		for rows.Next() {
+			var index int
+			var item models.MasterDataItem
+			if err := rows.Scan(&index, &item.ID, &item.Name); err != nil {
+				return nil, nil, err
+			}
+			items[index] = item
+			found[index] = true
+		}
+		if err := rows.Err(); err != nil {
+			return nil, nil, err
+		}
 	}
 
-	query := fmt.Sprintf("SELECT %s FROM %s WHERE UPPER(%s) = UPPER($1)", idCol, tableAlias, nameCol)
-	
-	var id int
-	err := database.DB.QueryRow(query, name).Scan(&id)
-	
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return 0, fmt.Errorf("%s with name '%s' not found", tableAlias, name)
+	var invalid []models.FieldError
+	for i, l := range lookups {
+		if l.empty() || found[i] {
+			continue
 		}
-		log.Printf("Database error fetching ID for %s: %v", tableAlias, err)
-		return 0, err
+		if l.ID != 0 {
+			id := strconv.Itoa(l.ID)
+			invalid = append(invalid, validation.NewFieldError(l.Field+"Id", validation.CodeInvalidChoice, id, id))
+			continue
+		}
+		invalid = append(invalid, validation.NewFieldError(l.Field, validation.CodeInvalidChoice, l.Name, l.Name))
 	}
-	return id, nil
-}
\ No newline at end of file
+	return items, invalid, nil
+}
@@ -0,0 +1,389 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"mantest/backend/internal/database"
+	"mantest/backend/internal/models"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	mfaChallengeTTL    = 5 * time.Minute
+	recoveryCodeCount  = 10
+	defaultTOTPIssuer  = "Manpower"
+	tokenUseAccess     = "access"
+	tokenUseMFAPending = "mfa_challenge"
+
+	LoginFailureBadMFACode = "bad_mfa_code"
+)
+
+var (
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnrolled    = errors.New("two-factor authentication is not enrolled")
+	ErrMFAInvalidCode    = errors.New("invalid two-factor authentication code")
+	ErrMFARequired       = errors.New("two-factor authentication is required for this role")
+)
+
+func totpIssuer() string {
+	if issuer := os.Getenv("TOTP_ISSUER"); issuer != "" {
+		return issuer
+	}
+	return defaultTOTPIssuer
+}
+
+func issueMFAChallenge(employeeID, email, roleName string) (string, error) {
+	claims := models.AuthClaims{
+		EmployeeID:       employeeID,
+		Email:            email,
+		RoleName:         roleName,
+		TokenUse:         tokenUseMFAPending,
+		RegisteredClaims: registeredClaims(employeeID, mfaChallengeTTL),
+	}
+	return signClaims(claims)
+}
+
+func parseMFAChallenge(challengeToken string) (*models.AuthClaims, error) {
+	claims := &models.AuthClaims{}
+	if err := parseSignedClaims(challengeToken, claims); err != nil {
+		return nil, err
+	}
+	if claims.TokenUse != tokenUseMFAPending || claims.EmployeeID == "" {
+		return nil, ErrTokenInvalid
+	}
+	return claims, nil
+}
+
+func GetMFAStatus(employeeID string) (*models.MFAStatus, error) {
+	var status models.MFAStatus
+	err := database.DB.QueryRow(`
+		SELECT COALESCE(m.enabled, FALSE), r.mfa_required
+		FROM employees e
+		JOIN roles r ON r.role_id = e.role_id
+		LEFT JOIN employee_mfa m ON m.employee_id = e.employee_id
+		WHERE e.employee_id = $1
+	`, employeeID).Scan(&status.Enabled, &status.Required)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrEmployeeNotFound
+		}
+		return nil, err
+	}
+	return &status, nil
+}
+
+func BeginMFAEnrollment(employeeID, email string) (*models.MFAEnrollment, error) {
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := database.DB.Exec(`
+		INSERT INTO employee_mfa (employee_id, totp_secret, enabled)
+		VALUES ($1, $2, FALSE)
+		ON CONFLICT (employee_id) DO UPDATE
+		SET totp_secret = EXCLUDED.totp_secret, last_used_step = 0, created_at = CURRENT_TIMESTAMP
+		WHERE employee_mfa.enabled = FALSE
+	`, employeeID, secret)
+	if err != nil {
+		log.Printf("Failed to start MFA enrollment for employee %s: %v", employeeID, err)
+		return nil, errors.New("Failed to start two-factor enrollment.")
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	return &models.MFAEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totpProvisioningURI(totpIssuer(), email, secret),
+	}, nil
+}
+
+func ConfirmMFAEnrollment(employeeID, code string) ([]string, error) {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var secret string
+	var enabled bool
+	err = tx.QueryRow(`
+		SELECT totp_secret, enabled FROM employee_mfa WHERE employee_id = $1 FOR UPDATE
+	`, employeeID).Scan(&secret, &enabled)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMFANotEnrolled
+		}
+		return nil, err
+	}
+	if enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	step, ok := verifyTOTP(secret, code, time.Now())
+	if !ok {
+		return nil, ErrMFAInvalidCode
+	}
+
+	_, err = tx.Exec(`
+		UPDATE employee_mfa SET enabled = TRUE, last_used_step = $1, enrolled_at = CURRENT_TIMESTAMP
+		WHERE employee_id = $2
+	`, step, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err := replaceRecoveryCodes(tx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Two-factor authentication enabled for employee %s", employeeID)
+	return codes, nil
+}
+
+func replaceRecoveryCodes(tx *sql.Tx, employeeID string) ([]string, error) {
+	if _, err := tx.Exec(`DELETE FROM mfa_recovery_codes WHERE employee_id = $1`, employeeID); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw, err := generateOpaqueToken(5)
+		if err != nil {
+			return nil, err
+		}
+		code := raw[:5] + "-" + raw[5:]
+		_, err = tx.Exec(`
+			INSERT INTO mfa_recovery_codes (employee_id, code_hash) VALUES ($1, $2)
+		`, employeeID, hashOpaqueToken(normalizeRecoveryCode(code)))
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
+
+func verifySecondFactor(tx *sql.Tx, employeeID, code string) error {
+	var secret string
+	var enabled bool
+	var lastStep int64
+	err := tx.QueryRow(`
+		SELECT totp_secret, enabled, last_used_step FROM employee_mfa WHERE employee_id = $1 FOR UPDATE
+	`, employeeID).Scan(&secret, &enabled, &lastStep)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrMFANotEnrolled
+		}
+		return err
+	}
+	if !enabled {
+		return ErrMFANotEnrolled
+	}
+
+	if step, ok := verifyTOTP(secret, code, time.Now()); ok {
+		if step <= lastStep {
+			return ErrMFAInvalidCode
+		}
+		_, err := tx.Exec(`UPDATE employee_mfa SET last_used_step = $1 WHERE employee_id = $2`, step, employeeID)
+		return err
+	}
+
+	result, err := tx.Exec(`
+		UPDATE mfa_recovery_codes SET used_at = CURRENT_TIMESTAMP
+		WHERE code_id = (
+			SELECT code_id FROM mfa_recovery_codes
+			WHERE employee_id = $1 AND code_hash = $2 AND used_at IS NULL
+			LIMIT 1
+		)
+	`, employeeID, hashOpaqueToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrMFAInvalidCode
+	}
+	log.Printf("Recovery code used for employee %s", employeeID)
+	return nil
+}
+
+func checkSecondFactor(employeeID, code string) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := verifySecondFactor(tx, employeeID, code); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func CompleteMFALogin(challengeToken, code string, client models.ClientInfo) (*models.AuthResponse, error) {
+	claims, err := parseMFAChallenge(challengeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	throttled, err := checkLoginAllowed(claims.Email, client.IPAddress)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+	if throttled != nil {
+		recordLoginEvent(claims.Email, claims.EmployeeID, false, LoginFailureThrottled, client)
+		return nil, throttled
+	}
+
+	if err := checkSecondFactor(claims.EmployeeID, code); err != nil {
+		if errors.Is(err, ErrMFAInvalidCode) {
+			registerLoginFailure(claims.Email, client.IPAddress)
+			recordLoginEvent(claims.Email, claims.EmployeeID, false, LoginFailureBadMFACode, client)
+		}
+		return nil, err
+	}
+
+	return finishLogin(claims.EmployeeID, claims.Email, claims.RoleName, client)
+}
+
+func BeginChallengeEnrollment(challengeToken string) (*models.MFAEnrollment, error) {
+	claims, err := parseMFAChallenge(challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	return BeginMFAEnrollment(claims.EmployeeID, claims.Email)
+}
+
+func ConfirmChallengeEnrollment(challengeToken, code string, client models.ClientInfo) (*models.AuthResponse, error) {
+	claims, err := parseMFAChallenge(challengeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err := ConfirmMFAEnrollment(claims.EmployeeID, code)
+	if err != nil {
+		if errors.Is(err, ErrMFAInvalidCode) {
+			registerLoginFailure(claims.Email, client.IPAddress)
+			recordLoginEvent(claims.Email, claims.EmployeeID, false, LoginFailureBadMFACode, client)
+		}
+		return nil, err
+	}
+
+	response, err := finishLogin(claims.EmployeeID, claims.Email, claims.RoleName, client)
+	if err != nil {
+		return nil, err
+	}
+	response.RecoveryCodes = codes
+	return response, nil
+}
+
+func DisableMFA(employeeID, code string) error {
+	status, err := GetMFAStatus(employeeID)
+	if err != nil {
+		return err
+	}
+	if status.Required {
+		return ErrMFARequired
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := verifySecondFactor(tx, employeeID, code); err != nil {
+		return err
+	}
+	if err := deleteMFA(tx, employeeID); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	log.Printf("Two-factor authentication disabled for employee %s", employeeID)
+	return nil
+}
+
+func RegenerateRecoveryCodes(employeeID, code string) ([]string, error) {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := verifySecondFactor(tx, employeeID, code); err != nil {
+		return nil, err
+	}
+	codes, err := replaceRecoveryCodes(tx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func deleteMFA(tx *sql.Tx, employeeID string) error {
+	if _, err := tx.Exec(`DELETE FROM mfa_recovery_codes WHERE employee_id = $1`, employeeID); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`DELETE FROM employee_mfa WHERE employee_id = $1`, employeeID)
+	return err
+}
+
+func ResetEmployeeMFA(employeeID string) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM employees WHERE employee_id = $1)`, employeeID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrEmployeeNotFound
+	}
+
+	if err := deleteMFA(tx, employeeID); err != nil {
+		log.Printf("Failed to reset MFA for employee %s: %v", employeeID, err)
+		return errors.New("Failed to reset two-factor authentication.")
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	RevokeAllSessions(employeeID, "mfa reset")
+	log.Printf("Two-factor authentication reset for employee %s", employeeID)
+	return nil
+}
+
+func SetRoleMFARequired(roleName string, required bool) error {
+	result, err := database.DB.Exec(`UPDATE roles SET mfa_required = $1 WHERE UPPER(role_name) = UPPER($2)`, required, roleName)
+	if err != nil {
+		log.Printf("Failed to update MFA policy for role %s: %v", roleName, err)
+		return errors.New("Failed to update role MFA policy.")
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrRoleNotFound
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"mantest/backend/internal/database"
+	"mantest/backend/internal/models"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+func createNotification(tx *sql.Tx, employeeID string, requestID int, kind, message string) error {
+	_, err := tx.Exec(`
+		INSERT INTO notifications (employee_id, request_id, kind, message)
+		VALUES ($1, $2, $3, $4)
+	`, employeeID, requestID, kind, message)
+	return err
+}
+
+func GetNotifications(employeeID string, unreadOnly bool) ([]models.Notification, error) {
+	rows, err := database.DB.Query(`
+		SELECT notification_id, request_id, kind, message, created_at, read_at
+		FROM notifications
+		WHERE employee_id = $1 AND ($2 = FALSE OR read_at IS NULL)
+		ORDER BY created_at DESC, notification_id DESC
+		LIMIT 100
+	`, employeeID, unreadOnly)
+	if err != nil {
+		log.Printf("Error querying notifications for employee %s: %v", employeeID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+	for rows.Next() {
+		var n models.Notification
+		var requestID sql.NullInt64
+		var readAt sql.NullTime
+		if err := rows.Scan(&n.NotificationID, &requestID, &n.Kind, &n.Message, &n.CreatedAt, &readAt); err != nil {
+			log.Printf("Error scanning notification row: %v", err)
+			return nil, err
+		}
+		if requestID.Valid {
+			id := int(requestID.Int64)
+			n.RequestID = &id
+		}
+		if readAt.Valid {
+			n.ReadAt = &readAt.Time
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, rows.Err()
+}
+
+func MarkNotificationRead(employeeID string, notificationID int) error {
+	result, err := database.DB.Exec(`
+		UPDATE notifications SET read_at = COALESCE(read_at, CURRENT_TIMESTAMP)
+		WHERE notification_id = $1 AND employee_id = $2
+	`, notificationID, employeeID)
+	if err != nil {
+		log.Printf("Error marking notification %d as read: %v", notificationID, err)
+		return errors.New("Failed to update notification.")
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"mantest/backend/internal/database"
+	"mantest/backend/internal/models"
+	"os"
+	"time"
+)
+
+const defaultPasswordResetTTL = time.Hour
+
+var ErrInvalidResetToken = errors.New("reset token is invalid or has expired")
+
+func passwordResetTTL() time.Duration {
+	if value := os.Getenv("PASSWORD_RESET_TTL"); value != "" {
+		ttl, err := time.ParseDuration(value)
+		if err == nil && ttl > 0 {
+			return ttl
+		}
+		log.Printf("Invalid PASSWORD_RESET_TTL %q, using default %s", value, defaultPasswordResetTTL)
+	}
+	return defaultPasswordResetTTL
+}
+
+func ForcePasswordReset(employeeID, issuedBy string) (*models.PasswordResetIssued, error) {
+	token, err := generateOpaqueToken(32)
+	if err != nil {
+		log.Printf("Failed to generate reset token for employee %s: %v", employeeID, err)
+		return nil, errors.New("Failed to issue password reset token.")
+	}
+	expiresAt := time.Now().Add(passwordResetTTL())
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE employees SET password_reset_required = TRUE WHERE employee_id = $1 AND status = 'Active'`, employeeID)
+	if err != nil {
+		log.Printf("Failed to flag password reset for employee %s: %v", employeeID, err)
+		return nil, errors.New("Failed to issue password reset token.")
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil, ErrEmployeeNotFound
+	}
+
+	_, err = tx.Exec(`UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP WHERE employee_id = $1 AND used_at IS NULL`, employeeID)
+	if err != nil {
+		log.Printf("Failed to revoke previous reset tokens for employee %s: %v", employeeID, err)
+		return nil, errors.New("Failed to issue password reset token.")
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO password_reset_tokens (employee_id, token_hash, issued_by, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`, employeeID, hashOpaqueToken(token), issuedBy, expiresAt)
+	if err != nil {
+		log.Printf("Failed to store reset token for employee %s: %v", employeeID, err)
+		return nil, errors.New("Failed to issue password reset token.")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	RevokeAllSessions(employeeID, "password reset forced")
+	log.Printf("Password reset issued for employee %s by %s", employeeID, issuedBy)
+	return &models.PasswordResetIssued{
+		EmployeeID: employeeID,
+		ResetToken: token,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+func RedeemPasswordReset(token, newPassword string) error {
+	passwordHash, err := HashPassword(newPassword)
+	if err != nil {
+		log.Printf("Failed to hash new password during reset: %v", err)
+		return errors.New("Failed to reset password.")
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var tokenID int
+	var employeeID string
+	err = tx.QueryRow(`
+		SELECT token_id, employee_id
+		FROM password_reset_tokens
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+		FOR UPDATE
+	`, hashOpaqueToken(token)).Scan(&tokenID, &employeeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidResetToken
+		}
+		log.Printf("Database error looking up reset token: %v", err)
+		return errors.New("Failed to reset password.")
+	}
+
+	if _, err := tx.Exec(`UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP WHERE token_id = $1`, tokenID); err != nil {
+		log.Printf("Failed to consume reset token %d: %v", tokenID, err)
+		return errors.New("Failed to reset password.")
+	}
+
+	_, err = tx.Exec(`
+		UPDATE employees
+		SET password = $1, password_reset_required = FALSE
+		WHERE employee_id = $2 AND status = 'Active'
+	`, passwordHash, employeeID)
+	if err != nil {
+		log.Printf("Failed to update password for employee %s: %v", employeeID, err)
+		return errors.New("Failed to reset password.")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	RevokeAllSessions(employeeID, "password reset")
+	log.Printf("Password reset completed for employee %s", employeeID)
+	return nil
+}
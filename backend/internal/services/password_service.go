@@ -0,0 +1,60 @@
+package services
+
+import (
+	"crypto/subtle"
+	"log"
+	"mantest/backend/internal/database"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const passwordHashCost = 12
+
+// Unknown emails are checked against this hash so they take as long to reject as wrong passwords.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), passwordHashCost)
+
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), passwordHashCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func isBcryptHash(stored string) bool {
+	return strings.HasPrefix(stored, "$2a$") || strings.HasPrefix(stored, "$2b$") || strings.HasPrefix(stored, "$2y$")
+}
+
+// VerifyPassword returns (match, needsRehash). Legacy plaintext rows always need a rehash.
+func VerifyPassword(stored, password string) (bool, bool) {
+	if !isBcryptHash(stored) {
+		match := subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1
+		return match, match
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(stored), []byte(password)); err != nil {
+		return false, false
+	}
+
+	cost, err := bcrypt.Cost([]byte(stored))
+	return true, err != nil || cost < passwordHashCost
+}
+
+func rejectUnknownPassword(password string) {
+	bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+}
+
+func rehashPassword(employeeID, password string) {
+	hash, err := HashPassword(password)
+	if err != nil {
+		log.Printf("Failed to rehash password for employee %s: %v", employeeID, err)
+		return
+	}
+
+	if _, err := database.DB.Exec(`UPDATE employees SET password = $1 WHERE employee_id = $2`, hash, employeeID); err != nil {
+		log.Printf("Failed to store rehashed password for employee %s: %v", employeeID, err)
+		return
+	}
+	log.Printf("Upgraded stored password hash for employee %s", employeeID)
+}
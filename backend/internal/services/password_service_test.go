@@ -0,0 +1,42 @@
+package services
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestVerifyPassword(t *testing.T) {
+	current, err := HashPassword("s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	weak, err := bcrypt.GenerateFromPassword([]byte("s3cret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		stored      string
+		password    string
+		match       bool
+		needsRehash bool
+	}{
+		{"current hash", current, "s3cret", true, false},
+		{"current hash, wrong password", current, "wrong", false, false},
+		{"weaker cost", string(weak), "s3cret", true, true},
+		{"weaker cost, wrong password", string(weak), "wrong", false, false},
+		{"legacy plaintext", "s3cret", "s3cret", true, true},
+		{"legacy plaintext, wrong password", "s3cret", "wrong", false, false},
+		{"legacy plaintext, empty password", "s3cret", "", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, needsRehash := VerifyPassword(tt.stored, tt.password)
+			if match != tt.match || needsRehash != tt.needsRehash {
+				t.Errorf("VerifyPassword() = (%v, %v), want (%v, %v)", match, needsRehash, tt.match, tt.needsRehash)
+			}
+		})
+	}
+}
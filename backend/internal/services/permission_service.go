@@ -0,0 +1,216 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"mantest/backend/internal/database"
+	"mantest/backend/internal/models"
+	"strings"
+	"sync"
+	"time"
+)
+
+const permissionCacheTTL = time.Minute
+
+// adminRoleName is the role that must always be able to manage roles, so
+// that role management cannot be locked away from everyone.
+const adminRoleName = "Admin"
+
+var (
+	ErrRoleNotFound           = errors.New("role not found")
+	ErrInvalidRolePermissions = errors.New("invalid role permissions")
+	ErrAdminLockout           = fmt.Errorf("the %s role must keep %s", adminRoleName, models.PermRolesManage)
+)
+
+type cachedPermissions struct {
+	permissions map[string]bool
+	loadedAt    time.Time
+}
+
+var (
+	permissionCache   = make(map[string]cachedPermissions)
+	permissionCacheMu sync.RWMutex
+)
+
+func loadRolePermissions(roleName string) (map[string]bool, error) {
+	query := `
+        SELECT p.permission_code
+        FROM roles r
+        JOIN role_permissions rp ON rp.role_id = r.role_id
+        JOIN permissions p ON p.permission_id = rp.permission_id
+        WHERE UPPER(r.role_name) = UPPER($1) AND r.status = 'Active'
+    `
+
+	rows, err := database.DB.Query(query, roleName)
+	if err != nil {
+		log.Printf("Error querying permissions for role %s: %v", roleName, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := make(map[string]bool)
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			log.Printf("Error scanning permission row for role %s: %v", roleName, err)
+			return nil, err
+		}
+		permissions[code] = true
+	}
+	return permissions, rows.Err()
+}
+
+func getRolePermissionSet(roleName string) (map[string]bool, error) {
+	key := strings.ToUpper(roleName)
+
+	permissionCacheMu.RLock()
+	cached, ok := permissionCache[key]
+	permissionCacheMu.RUnlock()
+	if ok && time.Since(cached.loadedAt) < permissionCacheTTL {
+		return cached.permissions, nil
+	}
+
+	permissions, err := loadRolePermissions(roleName)
+	if err != nil {
+		return nil, err
+	}
+
+	permissionCacheMu.Lock()
+	permissionCache[key] = cachedPermissions{permissions: permissions, loadedAt: time.Now()}
+	permissionCacheMu.Unlock()
+	return permissions, nil
+}
+
+func InvalidatePermissionCache() {
+	permissionCacheMu.Lock()
+	permissionCache = make(map[string]cachedPermissions)
+	permissionCacheMu.Unlock()
+}
+
+func GetPermissionsByRole(roleName string) ([]string, error) {
+	set, err := getRolePermissionSet(roleName)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := make([]string, 0, len(set))
+	for code := range set {
+		permissions = append(permissions, code)
+	}
+	return permissions, nil
+}
+
+func RoleHasPermission(roleName, permission string) (bool, error) {
+	set, err := getRolePermissionSet(roleName)
+	if err != nil {
+		return false, err
+	}
+	return set[permission], nil
+}
+
+func GetAllRolePermissions() ([]models.RolePermissions, error) {
+	query := `
+        SELECT r.role_name, COALESCE(p.permission_code, '')
+        FROM roles r
+        LEFT JOIN role_permissions rp ON rp.role_id = r.role_id
+        LEFT JOIN permissions p ON p.permission_id = rp.permission_id
+        ORDER BY r.role_id ASC, p.permission_code ASC
+    `
+
+	rows, err := database.DB.Query(query)
+	if err != nil {
+		log.Printf("Error querying role permissions: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []models.RolePermissions
+	index := make(map[string]int)
+	for rows.Next() {
+		var roleName, code string
+		if err := rows.Scan(&roleName, &code); err != nil {
+			log.Printf("Error scanning role permission row: %v", err)
+			return nil, err
+		}
+
+		i, ok := index[roleName]
+		if !ok {
+			result = append(result, models.RolePermissions{Role: roleName, Permissions: []string{}})
+			i = len(result) - 1
+			index[roleName] = i
+		}
+		if code != "" {
+			result[i].Permissions = append(result[i].Permissions, code)
+		}
+	}
+	return result, rows.Err()
+}
+
+func SetRolePermissions(roleName string, permissions []string) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var roleID int
+	err = tx.QueryRow(`SELECT role_id FROM roles WHERE UPPER(role_name) = UPPER($1)`, roleName).Scan(&roleID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: unknown role %s", ErrInvalidRolePermissions, roleName)
+		}
+		return err
+	}
+
+	if strings.EqualFold(roleName, adminRoleName) && !containsString(permissions, models.PermRolesManage) {
+		return ErrAdminLockout
+	}
+
+	permissionIDs := make([]int, 0, len(permissions))
+	for _, code := range permissions {
+		var permissionID int
+		err := tx.QueryRow(`SELECT permission_id FROM permissions WHERE permission_code = $1`, code).Scan(&permissionID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: unknown permission code %s", ErrInvalidRolePermissions, code)
+			}
+			return err
+		}
+		permissionIDs = append(permissionIDs, permissionID)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM role_permissions WHERE role_id = $1`, roleID); err != nil {
+		log.Printf("Error clearing permissions for role %s: %v", roleName, err)
+		return errors.New("Failed to update role permissions.")
+	}
+
+	for _, permissionID := range permissionIDs {
+		_, err := tx.Exec(`
+			INSERT INTO role_permissions (role_id, permission_id)
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+		`, roleID, permissionID)
+		if err != nil {
+			log.Printf("Error assigning permission %d to role %s: %v", permissionID, roleName, err)
+			return errors.New("Failed to update role permissions.")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	InvalidatePermissionCache()
+	return nil
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
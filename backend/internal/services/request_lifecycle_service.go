@@ -0,0 +1,188 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"mantest/backend/internal/database"
+	"mantest/backend/internal/models"
+)
+
+var (
+	ErrInvalidTransition = errors.New("request cannot change to that status")
+	ErrNotRequester      = errors.New("only the requester can change this request")
+)
+
+const withdrawalStepName = "ถอนคำร้อง"
+
+// requestTransitions lists, for each lifecycle state, the states it may move
+// to. Any change not listed here is refused.
+var requestTransitions = map[string][]string{
+	models.RequestDraft:      {models.RequestSubmitted, models.RequestCancelled},
+	models.RequestSubmitted:  {models.RequestInApproval, models.RequestCancelled},
+	models.RequestInApproval: {models.RequestReturned, models.RequestApproved, models.RequestRejected, models.RequestCancelled},
+	models.RequestReturned:   {models.RequestSubmitted, models.RequestCancelled},
+	models.RequestApproved:   {models.RequestFilled},
+}
+
+func canTransition(from, to string) bool {
+	for _, allowed := range requestTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// transitionRequest moves a request to a new lifecycle state and logs the
+// change. actorID may be empty for changes made by the system.
+func transitionRequest(tx *sql.Tx, requestID int, to, actorID, notes string) error {
+	var from string
+	err := tx.QueryRow(`
+		SELECT lifecycle_status FROM manpower_requests WHERE request_id = $1 FOR UPDATE
+	`, requestID).Scan(&from)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrRequestNotFound
+		}
+		return err
+	}
+	if !canTransition(from, to) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, from, to)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE manpower_requests SET lifecycle_status = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE request_id = $2
+	`, to, requestID)
+	if err != nil {
+		return err
+	}
+
+	var sqlActor, sqlNotes sql.NullString
+	if actorID != "" {
+		sqlActor = sql.NullString{String: actorID, Valid: true}
+	}
+	if notes != "" {
+		sqlNotes = sql.NullString{String: notes, Valid: true}
+	}
+	_, err = tx.Exec(`
+		INSERT INTO request_status_transitions (request_id, from_status, to_status, actor_id, notes)
+		VALUES ($1, $2, $3, $4, $5)
+	`, requestID, from, to, sqlActor, sqlNotes)
+	return err
+}
+
+// lockOwnRequest locks the request row and checks that employeeID raised it.
+func lockOwnRequest(tx *sql.Tx, requestID int, employeeID string) error {
+	var requesterID string
+	err := tx.QueryRow(`
+		SELECT employee_id FROM manpower_requests WHERE request_id = $1 FOR UPDATE
+	`, requestID).Scan(&requesterID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrRequestNotFound
+		}
+		return err
+	}
+	if requesterID != employeeID {
+		return ErrNotRequester
+	}
+	return nil
+}
+
+// WithdrawRequest cancels a request on behalf of its requester at any point
+// before a final decision. Outstanding approval steps are skipped.
+func WithdrawRequest(requestID int, employeeID, reason string) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockOwnRequest(tx, requestID, employeeID); err != nil {
+		return err
+	}
+	if err := transitionRequest(tx, requestID, models.RequestCancelled, employeeID, reason); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE request_step_approvers SET status = $1
+		WHERE status = $2 AND step_id IN (
+			SELECT step_id FROM request_approval_steps WHERE request_id = $3 AND status IN ($4, $5)
+		)
+	`, models.StepStatusSkipped, models.StepStatusPending, requestID, models.StepStatusPending, models.StepStatusWaiting)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+		UPDATE request_approval_steps SET status = $1, completed_at = CURRENT_TIMESTAMP
+		WHERE request_id = $2 AND status IN ($3, $4)
+	`, models.StepStatusSkipped, requestID, models.StepStatusPending, models.StepStatusWaiting)
+	if err != nil {
+		return err
+	}
+
+	if err := recordApprovalHistory(tx, requestID, nil, employeeID, "", models.DecisionWithdrawn, reason, withdrawalStepName); err != nil {
+		return err
+	}
+	if err := setRequestStatus(tx, requestID, models.RequestStatusCancelled); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	log.Printf("Request %d withdrawn by %s", requestID, employeeID)
+	return nil
+}
+
+// MarkRequestFilled closes an approved request outright, recording every
+// position still open on its lines as filled.
+func MarkRequestFilled(requestID int, employeeID, notes string) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := transitionRequest(tx, requestID, models.RequestFilled, employeeID, notes); err != nil {
+		return err
+	}
+	if err := fillRemainingLines(tx, requestID, employeeID, notes); err != nil {
+		return err
+	}
+	if err := setRequestStatus(tx, requestID, models.RequestStatusFilled); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func GetRequestTransitions(requestID int) ([]models.RequestTransition, error) {
+	rows, err := database.DB.Query(`
+		SELECT t.transition_id, t.from_status, t.to_status, COALESCE(t.actor_id, ''),
+		       COALESCE(e.first_name || ' ' || e.last_name, ''), COALESCE(t.notes, ''), t.created_at
+		FROM request_status_transitions t
+		LEFT JOIN employees e ON e.employee_id = t.actor_id
+		WHERE t.request_id = $1
+		ORDER BY t.created_at ASC, t.transition_id ASC
+	`, requestID)
+	if err != nil {
+		log.Printf("Error querying status transitions for request %d: %v", requestID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	transitions := []models.RequestTransition{}
+	for rows.Next() {
+		var t models.RequestTransition
+		if err := rows.Scan(&t.TransitionID, &t.FromStatus, &t.ToStatus, &t.ActorID, &t.ActorName, &t.Notes, &t.CreatedAt); err != nil {
+			log.Printf("Error scanning status transition row: %v", err)
+			return nil, err
+		}
+		transitions = append(transitions, t)
+	}
+	return transitions, rows.Err()
+}
@@ -0,0 +1,263 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"mantest/backend/internal/database"
+	"mantest/backend/internal/models"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrRequestLineNotFound = errors.New("request line not found")
+	ErrLineOverfilled      = errors.New("quantity exceeds the positions still open on this line")
+)
+
+// saveRequestLines replaces the lines of a request that has not been
+// approved yet, so no fills are lost.
+func saveRequestLines(tx *sql.Tx, requestID int, lines []models.ManpowerRequestLineRecord) error {
+	if _, err := tx.Exec(`DELETE FROM manpower_request_lines WHERE request_id = $1`, requestID); err != nil {
+		return err
+	}
+
+	for i, line := range lines {
+		var targetDate sql.NullTime
+		if !line.TargetHireDate.IsZero() {
+			targetDate = sql.NullTime{Time: line.TargetHireDate, Valid: true}
+		}
+		_, err := tx.Exec(`
+			INSERT INTO manpower_request_lines (
+				request_id, line_no, pos_id, position_code, position_name,
+				quantity, qualifications, target_hire_date
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`, requestID, i+1, nullableInt(line.PosID), line.PositionCode, line.PositionName,
+			line.Quantity, line.Qualifications, targetDate)
+		if err != nil {
+			log.Printf("SQL INSERT Error: %v", err)
+			return errors.New("Failed to save request lines.")
+		}
+	}
+	return nil
+}
+
+func lineFillStatus(quantity, filled int) string {
+	switch {
+	case quantity > 0 && filled >= quantity:
+		return models.LineFilled
+	case filled > 0:
+		return models.LinePartial
+	default:
+		return models.LineOpen
+	}
+}
+
+// loadRequestLines returns the lines of each of the given requests, keyed by
+// request ID and in line order.
+func loadRequestLines(requestIDs []int) (map[int][]models.ManpowerRequestLine, error) {
+	lines := make(map[int][]models.ManpowerRequestLine, len(requestIDs))
+	if len(requestIDs) == 0 {
+		return lines, nil
+	}
+	ids := make([]int64, len(requestIDs))
+	for i, id := range requestIDs {
+		ids[i] = int64(id)
+		lines[id] = []models.ManpowerRequestLine{}
+	}
+
+	rows, err := database.DB.Query(`
+		SELECT l.request_id, l.line_id, l.line_no, l.pos_id, COALESCE(p.pos_name, l.position_name),
+		       l.position_code, l.quantity, l.filled_quantity, COALESCE(l.qualifications, ''), l.target_hire_date
+		FROM manpower_request_lines l
+		LEFT JOIN positions p ON p.pos_id = l.pos_id
+		WHERE l.request_id = ANY($1)
+		ORDER BY l.request_id, l.line_no
+	`, pq.Array(ids))
+	if err != nil {
+		log.Printf("Error querying request lines: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var requestID int
+		var line models.ManpowerRequestLine
+		var posID sql.NullInt64
+		var targetDate sql.NullTime
+		err := rows.Scan(
+			&requestID, &line.LineID, &line.LineNo, &posID, &line.PositionRequire,
+			&line.PositionId, &line.Quantity, &line.FilledQuantity, &line.Qualifications, &targetDate,
+		)
+		if err != nil {
+			log.Printf("Error scanning request line row: %v", err)
+			return nil, err
+		}
+		line.PositionRequireID = nullIntPtr(posID)
+		if targetDate.Valid {
+			line.TargetHireDate = targetDate.Time.Format("02/01/2006")
+		}
+		line.FillStatus = lineFillStatus(line.Quantity, line.FilledQuantity)
+		lines[requestID] = append(lines[requestID], line)
+	}
+	return lines, rows.Err()
+}
+
+// missingLineFields reports, by form field name, what the lines of a draft
+// still need before it can be submitted.
+func missingLineFields(tx *sql.Tx, draftID int) ([]string, error) {
+	rows, err := tx.Query(`
+		SELECT line_no, pos_id IS NULL, quantity = 0 FROM manpower_request_lines
+		WHERE request_id = $1
+		ORDER BY line_no
+	`, draftID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var missing []string
+	count := 0
+	for rows.Next() {
+		var lineNo int
+		var noPosition, noQuantity bool
+		if err := rows.Scan(&lineNo, &noPosition, &noQuantity); err != nil {
+			return nil, err
+		}
+		count++
+		if noPosition {
+			missing = append(missing, fmt.Sprintf("lines[%d].positionRequire", lineNo-1))
+		}
+		if noQuantity {
+			missing = append(missing, fmt.Sprintf("lines[%d].quantity", lineNo-1))
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		missing = append(missing, "lines")
+	}
+	return missing, nil
+}
+
+// recordLineFill adds quantity hires to a line and logs them.
+func recordLineFill(tx *sql.Tx, lineID, quantity int, employeeID, notes string) error {
+	var sqlNotes sql.NullString
+	if notes != "" {
+		sqlNotes = sql.NullString{String: notes, Valid: true}
+	}
+	_, err := tx.Exec(`
+		UPDATE manpower_request_lines SET filled_quantity = filled_quantity + $1
+		WHERE line_id = $2
+	`, quantity, lineID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+		INSERT INTO request_line_fills (line_id, quantity, filled_by, notes)
+		VALUES ($1, $2, $3, $4)
+	`, lineID, quantity, employeeID, sqlNotes)
+	return err
+}
+
+// FillRequestLine records hires against one line of an approved request.
+// Once every line is fully staffed the request moves to FILLED, which is
+// reported back.
+func FillRequestLine(requestID, lineID int, employeeID string, quantity int, notes string) (bool, error) {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var lifecycle string
+	err = tx.QueryRow(`
+		SELECT lifecycle_status FROM manpower_requests WHERE request_id = $1 FOR UPDATE
+	`, requestID).Scan(&lifecycle)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, ErrRequestNotFound
+		}
+		return false, err
+	}
+	if lifecycle != models.RequestApproved {
+		return false, fmt.Errorf("%w: only approved requests can be filled", ErrInvalidTransition)
+	}
+
+	var open int
+	err = tx.QueryRow(`
+		SELECT quantity - filled_quantity FROM manpower_request_lines
+		WHERE line_id = $1 AND request_id = $2
+	`, lineID, requestID).Scan(&open)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, ErrRequestLineNotFound
+		}
+		return false, err
+	}
+	if quantity > open {
+		return false, fmt.Errorf("%w (%d open)", ErrLineOverfilled, open)
+	}
+	if err := recordLineFill(tx, lineID, quantity, employeeID, notes); err != nil {
+		return false, err
+	}
+
+	var remaining int
+	err = tx.QueryRow(`
+		SELECT COALESCE(SUM(quantity - filled_quantity), 0) FROM manpower_request_lines WHERE request_id = $1
+	`, requestID).Scan(&remaining)
+	if err != nil {
+		return false, err
+	}
+	completed := remaining == 0
+	if completed {
+		if err := transitionRequest(tx, requestID, models.RequestFilled, employeeID, notes); err != nil {
+			return false, err
+		}
+		if err := setRequestStatus(tx, requestID, models.RequestStatusFilled); err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return completed, nil
+}
+
+// fillRemainingLines records every still-open position of a request as
+// filled.
+func fillRemainingLines(tx *sql.Tx, requestID int, employeeID, notes string) error {
+	rows, err := tx.Query(`
+		SELECT line_id, quantity - filled_quantity FROM manpower_request_lines
+		WHERE request_id = $1 AND filled_quantity < quantity
+		ORDER BY line_no
+	`, requestID)
+	if err != nil {
+		return err
+	}
+	type openLine struct{ lineID, open int }
+	var lines []openLine
+	for rows.Next() {
+		var l openLine
+		if err := rows.Scan(&l.lineID, &l.open); err != nil {
+			rows.Close()
+			return err
+		}
+		lines = append(lines, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, l := range lines {
+		if err := recordLineFill(tx, l.lineID, l.open, employeeID, notes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,395 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"mantest/backend/internal/database"
+	"mantest/backend/internal/models"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var ErrDuplicateDocNumber = errors.New("document number already exists")
+
+func CreateManpowerRequest(record *models.ManpowerRequestRecord) (int, error) {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if record.DocNumber == "" {
+		if record.DocNumber, err = NextDocumentNumber(tx, models.DocTypeManpowerRequest); err != nil {
+			return 0, err
+		}
+	}
+
+	query := `
+		INSERT INTO manpower_requests (
+			doc_number, employee_id, doc_date, requesting_dept_id, requesting_pos_id,
+			employment_type_id, contract_type_id, reason_id,
+			required_position_code, required_position_name, min_age, max_age,
+			gender_id, nationality_id, experience_id, education_level_id,
+			special_qualifications, headcount, lifecycle_status
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+		RETURNING request_id
+	`
+
+	var requestID int
+	err = tx.QueryRow(query,
+		record.DocNumber,
+		record.EmployeeID,
+		record.DocDate,
+		record.DeptID,
+		record.PosID,
+		record.EmploymentTypeID,
+		record.ContractTypeID,
+		record.ReasonID,
+		record.RequiredPositionCode,
+		record.RequiredPositionName,
+		record.MinAge,
+		record.MaxAge,
+		record.GenderID,
+		record.NationalityID,
+		record.ExperienceID,
+		record.EducationLevelID,
+		record.SpecialQualifications,
+		record.Headcount,
+		models.RequestDraft,
+	).Scan(&requestID)
+	if err != nil {
+		log.Printf("SQL INSERT Error: %v", err)
+		if strings.Contains(err.Error(), "manpower_requests_doc_number_key") {
+			return 0, ErrDuplicateDocNumber
+		}
+		return 0, errors.New("Failed to save manpower request to database.")
+	}
+	if err := saveRequestLines(tx, requestID, record.Lines); err != nil {
+		return 0, err
+	}
+
+	if err := submitRequest(tx, requestID, record.CreatedBy); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return requestID, nil
+}
+
+func submitRequest(tx *sql.Tx, requestID int, actorID string) error {
+	if err := transitionRequest(tx, requestID, models.RequestSubmitted, actorID, ""); err != nil {
+		return err
+	}
+	if err := StartWorkflow(tx, requestID); err != nil {
+		log.Printf("Failed to start approval workflow for request %d: %v", requestID, err)
+		return err
+	}
+	return nil
+}
+
+// ResubmitManpowerRequest replaces the details of a returned request with the
+// requester's edits and sends it through a fresh round of approval.
+func ResubmitManpowerRequest(requestID int, record *models.ManpowerRequestRecord) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockOwnRequest(tx, requestID, record.CreatedBy); err != nil {
+		return err
+	}
+
+	var lifecycle string
+	if err := tx.QueryRow(`SELECT lifecycle_status FROM manpower_requests WHERE request_id = $1`, requestID).Scan(&lifecycle); err != nil {
+		return err
+	}
+	if lifecycle != models.RequestReturned {
+		return fmt.Errorf("%w: only returned requests can be resubmitted", ErrInvalidTransition)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE manpower_requests SET
+			doc_date = $1, requesting_dept_id = $2, requesting_pos_id = $3,
+			employment_type_id = $4, contract_type_id = $5, reason_id = $6,
+			required_position_code = $7, required_position_name = $8, min_age = $9, max_age = $10,
+			gender_id = $11, nationality_id = $12, experience_id = $13, education_level_id = $14,
+			special_qualifications = $15, headcount = $16, updated_at = CURRENT_TIMESTAMP
+		WHERE request_id = $17
+	`,
+		record.DocDate,
+		record.DeptID,
+		record.PosID,
+		record.EmploymentTypeID,
+		record.ContractTypeID,
+		record.ReasonID,
+		record.RequiredPositionCode,
+		record.RequiredPositionName,
+		record.MinAge,
+		record.MaxAge,
+		record.GenderID,
+		record.NationalityID,
+		record.ExperienceID,
+		record.EducationLevelID,
+		record.SpecialQualifications,
+		record.Headcount,
+		requestID,
+	)
+	if err != nil {
+		log.Printf("SQL UPDATE Error: %v", err)
+		return errors.New("Failed to update manpower request.")
+	}
+	if err := saveRequestLines(tx, requestID, record.Lines); err != nil {
+		return err
+	}
+
+	if err := submitRequest(tx, requestID, record.CreatedBy); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// listableStatuses are the lifecycle states GetManpowerRequests accepts as a
+// filter. Drafts are private to their requester and listed with GetDrafts.
+var listableStatuses = []string{
+	models.RequestSubmitted, models.RequestInApproval, models.RequestReturned,
+	models.RequestApproved, models.RequestRejected, models.RequestCancelled, models.RequestFilled,
+}
+
+var ErrInvalidStatusFilter = fmt.Errorf("status must be one of %s", strings.Join(listableStatuses, ", "))
+
+func isListableStatus(status string) bool {
+	for _, s := range listableStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func GetManpowerRequests(filter models.ManpowerRequestFilter) (*models.ManpowerRequestList, error) {
+	if filter.LifecycleStatus != "" && !isListableStatus(filter.LifecycleStatus) {
+		return nil, ErrInvalidStatusFilter
+	}
+
+	// Discarded drafts never got a document number and are not listed.
+	conditions := []string{"r.lifecycle_status <> '" + models.RequestDraft + "'", "r.doc_number IS NOT NULL"}
+	var args []interface{}
+	addCondition := func(clause string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, strings.ReplaceAll(clause, "$?", fmt.Sprintf("$%d", len(args))))
+	}
+
+	if filter.ViewerID != "" {
+		addCondition(requestVisibility("$?"), filter.ViewerID)
+	}
+	if filter.AwaitingID != "" {
+		addCondition(`EXISTS (
+			SELECT 1 FROM request_step_approvers a
+			JOIN request_approval_steps s ON s.step_id = a.step_id
+			LEFT JOIN approver_delegations d ON d.employee_id = a.approver_id AND `+activeDelegation("d")+`
+			WHERE s.request_id = r.request_id AND s.status = 'Pending' AND a.status = 'Pending'
+			  AND (a.approver_id = $? OR d.delegate_id = $?)
+		)`, filter.AwaitingID)
+	}
+	if filter.LifecycleStatus != "" {
+		addCondition("r.lifecycle_status = $?", filter.LifecycleStatus)
+	}
+	if filter.DepartmentID != 0 {
+		addCondition("r.requesting_dept_id = $?", filter.DepartmentID)
+	}
+	if filter.RequesterID != "" {
+		addCondition("r.employee_id = $?", filter.RequesterID)
+	}
+	if filter.DocNumber != "" {
+		addCondition("r.doc_number ILIKE '%' || $? || '%'", filter.DocNumber)
+	}
+	if filter.From != nil {
+		addCondition("r.doc_date >= $?", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("r.doc_date <= $?", *filter.To)
+	}
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	list := &models.ManpowerRequestList{
+		Items:    []models.ManpowerRequestSummary{},
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+	}
+	err := database.DB.QueryRow(`SELECT COUNT(*) FROM manpower_requests r`+where, args...).Scan(&list.Total)
+	if err != nil {
+		log.Printf("Error counting manpower requests: %v", err)
+		return nil, err
+	}
+
+	// The due date shown is that of the step currently waiting on approvers.
+	query := `
+		SELECT r.request_id, COALESCE(r.doc_number, ''), r.doc_date,
+		       r.requesting_dept_id, COALESCE(d.dept_name, ''), r.required_position_name, r.headcount,
+		       (SELECT COALESCE(SUM(l.filled_quantity), 0) FROM manpower_request_lines l WHERE l.request_id = r.request_id),
+		       r.employee_id, COALESCE(e.first_name || ' ' || e.last_name, ''),
+		       COALESCE(r.current_status, ''), r.lifecycle_status,
+		       (SELECT s.due_at FROM request_approval_steps s
+		        WHERE s.request_id = r.request_id AND s.status = 'Pending'
+		        ORDER BY s.round DESC, s.step_order ASC LIMIT 1),
+		       r.created_at
+		FROM manpower_requests r
+		LEFT JOIN departments d ON d.dept_id = r.requesting_dept_id
+		LEFT JOIN employees e ON e.employee_id = r.employee_id
+	` + where
+	args = append(args, filter.PageSize, (filter.Page-1)*filter.PageSize)
+	query += fmt.Sprintf(" ORDER BY r.doc_date DESC, r.request_id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		log.Printf("Error querying manpower requests: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.ManpowerRequestSummary
+		var docDate time.Time
+		var dueAt sql.NullTime
+		var deptID sql.NullInt64
+		err := rows.Scan(
+			&item.RequestID, &item.DocNumber, &docDate,
+			&deptID, &item.Department, &item.PositionRequire, &item.Headcount, &item.FilledHeadcount,
+			&item.RequesterID, &item.RequesterName,
+			&item.CurrentStatus, &item.LifecycleStatus,
+			&dueAt, &item.CreatedAt,
+		)
+		if err != nil {
+			log.Printf("Error scanning manpower request row: %v", err)
+			return nil, err
+		}
+		item.DocumentDate = docDate.Format("02/01/2006")
+		item.DepartmentID = nullIntPtr(deptID)
+		if dueAt.Valid {
+			item.DueAt = &dueAt.Time
+		}
+		item.StepStatuses = map[string]string{}
+		list.Items = append(list.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := loadStepStatuses(list.Items); err != nil {
+		log.Printf("Error querying step statuses for manpower requests: %v", err)
+		return nil, err
+	}
+	return list, nil
+}
+
+// loadStepStatuses fills in the status of each step of the latest approval
+// round, keyed by step code, for the listed requests.
+func loadStepStatuses(items []models.ManpowerRequestSummary) error {
+	if len(items) == 0 {
+		return nil
+	}
+	index := make(map[int]*models.ManpowerRequestSummary, len(items))
+	ids := make([]int64, len(items))
+	for i := range items {
+		index[items[i].RequestID] = &items[i]
+		ids[i] = int64(items[i].RequestID)
+	}
+
+	rows, err := database.DB.Query(`
+		SELECT s.request_id, s.step_code, s.status
+		FROM request_approval_steps s
+		WHERE s.request_id = ANY($1)
+		  AND s.round = (SELECT MAX(l.round) FROM request_approval_steps l WHERE l.request_id = s.request_id)
+	`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var requestID int
+		var stepCode, status string
+		if err := rows.Scan(&requestID, &stepCode, &status); err != nil {
+			return err
+		}
+		index[requestID].StepStatuses[stepCode] = status
+	}
+	return rows.Err()
+}
+
+// GetManpowerRequest returns a submitted request with its names resolved, its
+// lines and its approval workflow. Drafts are reported as not found.
+func GetManpowerRequest(requestID int) (*models.ManpowerRequestDetail, error) {
+	var detail models.ManpowerRequestDetail
+	var docDate time.Time
+	var minAge, maxAge sql.NullInt64
+	var ids selectionIDs
+	err := database.DB.QueryRow(`
+		SELECT r.request_id, COALESCE(r.doc_number, ''), r.doc_date,
+		       r.employee_id, COALESCE(e.first_name || ' ' || e.last_name, ''),
+		       COALESCE(d.dept_name, ''), COALESCE(et.et_name, ''), COALESCE(ct.ct_name, ''), COALESCE(rr.rr_name, ''),
+		       r.required_position_code, r.required_position_name, r.min_age, r.max_age,
+		       COALESCE(g.gender_name, ''), COALESCE(n.nat_name, ''), COALESCE(x.exp_name, ''), COALESCE(ed.edu_name, ''),
+		       COALESCE(r.special_qualifications, ''), r.headcount,
+		       COALESCE(r.current_status, ''), r.lifecycle_status, r.created_at, r.updated_at,
+		       `+selectionIDColumns+`
+		FROM manpower_requests r
+		LEFT JOIN employees e ON e.employee_id = r.employee_id
+		LEFT JOIN departments d ON d.dept_id = r.requesting_dept_id
+		LEFT JOIN employment_types et ON et.et_id = r.employment_type_id
+		LEFT JOIN contract_types ct ON ct.ct_id = r.contract_type_id
+		LEFT JOIN request_reasons rr ON rr.rr_id = r.reason_id
+		LEFT JOIN genders g ON g.gender_id = r.gender_id
+		LEFT JOIN nationalities n ON n.nat_id = r.nationality_id
+		LEFT JOIN experiences x ON x.exp_id = r.experience_id
+		LEFT JOIN education_levels ed ON ed.edu_id = r.education_level_id
+		WHERE r.request_id = $1 AND r.lifecycle_status <> $2 AND r.doc_number IS NOT NULL
+	`, requestID, models.RequestDraft).Scan(append([]interface{}{
+		&detail.RequestID, &detail.DocNumber, &docDate,
+		&detail.RequesterID, &detail.RequesterName,
+		&detail.Department, &detail.EmploymentType, &detail.ContractType, &detail.RequestReason,
+		&detail.PositionId, &detail.PositionRequire, &minAge, &maxAge,
+		&detail.Gender, &detail.Nationality, &detail.Experience, &detail.EducationLevel,
+		&detail.SpecialQualifications, &detail.Headcount,
+		&detail.CurrentStatus, &detail.LifecycleStatus, &detail.CreatedAt, &detail.UpdatedAt,
+	}, ids.targets()...)...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRequestNotFound
+		}
+		log.Printf("Error querying manpower request %d: %v", requestID, err)
+		return nil, err
+	}
+	detail.DocumentDate = docDate.Format("02/01/2006")
+	ids.assign(
+		&detail.DepartmentID, &detail.EmploymentTypeID, &detail.ContractTypeID, &detail.RequestReasonID,
+		&detail.PositionRequireID, &detail.GenderID, &detail.NationalityID, &detail.ExperienceID, &detail.EducationLevelID,
+	)
+	if minAge.Valid {
+		age := int(minAge.Int64)
+		detail.AgeFrom = &age
+	}
+	if maxAge.Valid {
+		age := int(maxAge.Int64)
+		detail.AgeTo = &age
+	}
+
+	lines, err := loadRequestLines([]int{requestID})
+	if err != nil {
+		return nil, err
+	}
+	detail.Lines = lines[requestID]
+
+	if detail.Workflow, err = GetWorkflowState(requestID); err != nil {
+		return nil, err
+	}
+	return &detail, nil
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"mantest/backend/internal/database"
+	"mantest/backend/internal/models"
+	"strings"
+)
+
+var (
+	ErrRoutingRuleNotFound = errors.New("routing rule not found")
+	ErrInvalidRoutingRule  = errors.New("invalid routing rule")
+)
+
+const routingRuleSelect = `
+	SELECT r.rule_id, r.rule_name, r.priority,
+	       r.dept_id, COALESCE(d.dept_name, ''),
+	       r.employment_type_id, COALESCE(et.et_name, ''),
+	       r.contract_type_id, COALESCE(ct.ct_name, ''),
+	       r.reason_id, COALESCE(rr.rr_name, ''),
+	       r.min_headcount, r.max_headcount, r.action, r.step_code, r.status,
+	       r.created_at, r.updated_at
+	FROM workflow_routing_rules r
+	LEFT JOIN departments d ON r.dept_id = d.dept_id
+	LEFT JOIN employment_types et ON r.employment_type_id = et.et_id
+	LEFT JOIN contract_types ct ON r.contract_type_id = ct.ct_id
+	LEFT JOIN request_reasons rr ON r.reason_id = rr.rr_id
+`
+
+func nullIntPtr(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	i := int(v.Int64)
+	return &i
+}
+
+func scanRoutingRules(rows *sql.Rows) ([]models.RoutingRule, error) {
+	rules := []models.RoutingRule{}
+	for rows.Next() {
+		var rule models.RoutingRule
+		var deptID, etID, ctID, reasonID, minHC, maxHC sql.NullInt64
+		if err := rows.Scan(
+			&rule.RuleID, &rule.RuleName, &rule.Priority,
+			&deptID, &rule.Department,
+			&etID, &rule.EmploymentType,
+			&ctID, &rule.ContractType,
+			&reasonID, &rule.RequestReason,
+			&minHC, &maxHC, &rule.Action, &rule.StepCode, &rule.Status,
+			&rule.CreatedAt, &rule.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		rule.DeptID = nullIntPtr(deptID)
+		rule.EmploymentTypeID = nullIntPtr(etID)
+		rule.ContractTypeID = nullIntPtr(ctID)
+		rule.ReasonID = nullIntPtr(reasonID)
+		rule.MinHeadcount = nullIntPtr(minHC)
+		rule.MaxHeadcount = nullIntPtr(maxHC)
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+// matchRoutingRules returns the active rules whose conditions all hold for
+// the request, lowest priority first. A NULL condition matches anything, so
+// later rules override earlier ones when they target the same step.
+func matchRoutingRules(q dbQuerier, req workflowRequest) ([]models.RoutingRule, error) {
+	rows, err := q.Query(routingRuleSelect+`
+		WHERE r.status = 'Active'
+		  AND (r.dept_id IS NULL OR r.dept_id = $1)
+		  AND (r.employment_type_id IS NULL OR r.employment_type_id = $2)
+		  AND (r.contract_type_id IS NULL OR r.contract_type_id = $3)
+		  AND (r.reason_id IS NULL OR r.reason_id = $4)
+		  AND (r.min_headcount IS NULL OR r.min_headcount <= $5)
+		  AND (r.max_headcount IS NULL OR r.max_headcount >= $5)
+		ORDER BY r.priority ASC, r.rule_id ASC
+	`, req.deptID, req.employmentTypeID, req.contractTypeID, req.reasonID, req.headcount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanRoutingRules(rows)
+}
+
+func GetRoutingRules() ([]models.RoutingRule, error) {
+	rows, err := database.DB.Query(routingRuleSelect + `ORDER BY r.priority ASC, r.rule_id ASC`)
+	if err != nil {
+		log.Printf("Error fetching routing rules: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+	return scanRoutingRules(rows)
+}
+
+func validateRoutingRule(input *models.RoutingRuleInput) error {
+	if input.MinHeadcount != nil && input.MaxHeadcount != nil && *input.MinHeadcount > *input.MaxHeadcount {
+		return fmt.Errorf("%w: minHeadcount must not be greater than maxHeadcount", ErrInvalidRoutingRule)
+	}
+
+	var exists bool
+	err := database.DB.QueryRow(
+		`SELECT EXISTS (SELECT 1 FROM workflow_step_definitions WHERE step_code = $1 AND status = 'Active')`,
+		input.StepCode,
+	).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: unknown step code %s", ErrInvalidRoutingRule, input.StepCode)
+	}
+	return nil
+}
+
+func CreateRoutingRule(input *models.RoutingRuleInput) (int, error) {
+	if err := validateRoutingRule(input); err != nil {
+		return 0, err
+	}
+	if input.Status == "" {
+		input.Status = "Active"
+	}
+
+	var ruleID int
+	err := database.DB.QueryRow(`
+		INSERT INTO workflow_routing_rules (
+			rule_name, priority, dept_id, employment_type_id, contract_type_id, reason_id,
+			min_headcount, max_headcount, action, step_code, status
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING rule_id
+	`, input.RuleName, input.Priority, input.DeptID, input.EmploymentTypeID, input.ContractTypeID, input.ReasonID,
+		input.MinHeadcount, input.MaxHeadcount, input.Action, input.StepCode, input.Status,
+	).Scan(&ruleID)
+	if err != nil {
+		log.Printf("Error creating routing rule: %v", err)
+		if strings.Contains(err.Error(), "violates foreign key constraint") {
+			return 0, fmt.Errorf("%w: unknown condition value", ErrInvalidRoutingRule)
+		}
+		return 0, errors.New("Failed to save routing rule.")
+	}
+	return ruleID, nil
+}
+
+func UpdateRoutingRule(ruleID int, input *models.RoutingRuleInput) error {
+	if err := validateRoutingRule(input); err != nil {
+		return err
+	}
+	if input.Status == "" {
+		input.Status = "Active"
+	}
+
+	result, err := database.DB.Exec(`
+		UPDATE workflow_routing_rules
+		SET rule_name = $1, priority = $2, dept_id = $3, employment_type_id = $4, contract_type_id = $5,
+		    reason_id = $6, min_headcount = $7, max_headcount = $8, action = $9, step_code = $10,
+		    status = $11, updated_at = CURRENT_TIMESTAMP
+		WHERE rule_id = $12
+	`, input.RuleName, input.Priority, input.DeptID, input.EmploymentTypeID, input.ContractTypeID, input.ReasonID,
+		input.MinHeadcount, input.MaxHeadcount, input.Action, input.StepCode, input.Status, ruleID,
+	)
+	if err != nil {
+		log.Printf("Error updating routing rule %d: %v", ruleID, err)
+		if strings.Contains(err.Error(), "violates foreign key constraint") {
+			return fmt.Errorf("%w: unknown condition value", ErrInvalidRoutingRule)
+		}
+		return errors.New("Failed to update routing rule.")
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrRoutingRuleNotFound
+	}
+	return nil
+}
+
+// DeactivateRoutingRule disables a rule rather than deleting it so the rule
+// set that routed past requests can still be reconstructed.
+func DeactivateRoutingRule(ruleID int) error {
+	result, err := database.DB.Exec(`
+		UPDATE workflow_routing_rules
+		SET status = 'Inactive', updated_at = CURRENT_TIMESTAMP
+		WHERE rule_id = $1
+	`, ruleID)
+	if err != nil {
+		log.Printf("Error deactivating routing rule %d: %v", ruleID, err)
+		return errors.New("Failed to delete routing rule.")
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrRoutingRuleNotFound
+	}
+	return nil
+}
+
+// DryRunRouting plans the approval chain for a hypothetical request without
+// writing anything, so admins can check a rule set before relying on it.
+func DryRunRouting(input *models.RoutingDryRunRequest) (*models.RoutingDryRunResult, error) {
+	req := workflowRequest{
+		requesterID:      input.RequesterID,
+		deptID:           input.DeptID,
+		employmentTypeID: input.EmploymentTypeID,
+		contractTypeID:   input.ContractTypeID,
+		reasonID:         input.ReasonID,
+		headcount:        input.Headcount,
+	}
+	if req.headcount <= 0 {
+		req.headcount = 1
+	}
+
+	plan, rules, err := planWorkflow(database.DB, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.RoutingDryRunResult{MatchedRules: rules}
+	for _, step := range plan {
+		result.Steps = append(result.Steps, models.PlannedApprovalStep{
+			StepOrder:         step.def.order,
+			StepCode:          step.def.code,
+			StepName:          step.def.name,
+			PendingStatus:     step.def.pendingStatus,
+			CompletionPolicy:  step.def.completionPolicy,
+			RequiredApprovals: step.required,
+			ApproverIDs:       step.approverIDs,
+			Skipped:           step.skipped,
+			SkipReason:        step.skipReason,
+		})
+	}
+	return result, nil
+}
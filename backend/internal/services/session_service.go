@@ -0,0 +1,256 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"mantest/backend/internal/database"
+	"mantest/backend/internal/models"
+	"os"
+	"time"
+)
+
+const defaultRefreshTokenTTL = 7 * 24 * time.Hour
+
+var ErrSessionRevoked = errors.New("session has been revoked")
+
+func refreshTokenTTL() time.Duration {
+	if value := os.Getenv("JWT_REFRESH_EXPIRY"); value != "" {
+		ttl, err := time.ParseDuration(value)
+		if err == nil && ttl > 0 {
+			return ttl
+		}
+		log.Printf("Invalid JWT_REFRESH_EXPIRY %q, using default %s", value, defaultRefreshTokenTTL)
+	}
+	return defaultRefreshTokenTTL
+}
+
+func insertRefreshToken(tx *sql.Tx, sessionID string, expiresAt time.Time) (string, error) {
+	refreshToken, err := generateOpaqueToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO refresh_tokens (session_id, token_hash, expires_at)
+		VALUES ($1, $2, $3)
+	`, sessionID, hashOpaqueToken(refreshToken), expiresAt)
+	if err != nil {
+		return "", err
+	}
+	return refreshToken, nil
+}
+
+func signAccessToken(employeeID, email, roleName, sessionID string) (string, error) {
+	claims := models.AuthClaims{
+		EmployeeID:       employeeID,
+		Email:            email,
+		RoleName:         roleName,
+		SessionID:        sessionID,
+		TokenUse:         tokenUseAccess,
+		RegisteredClaims: registeredClaims(employeeID, tokenExpiry()),
+	}
+	return signClaims(claims)
+}
+
+func startSession(employeeID, email, roleName string, client models.ClientInfo) (*models.AuthResponse, error) {
+	sessionID, err := generateOpaqueToken(16)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(refreshTokenTTL())
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO auth_sessions (session_id, employee_id, ip_address, user_agent, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, sessionID, employeeID, client.IPAddress, client.UserAgent, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	refreshToken, err := insertRefreshToken(tx, sessionID, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	accessToken, err := signAccessToken(employeeID, email, roleName, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign access token: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &models.AuthResponse{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(tokenExpiry().Seconds()),
+		Role:         roleName,
+		Email:        email,
+	}, nil
+}
+
+func RefreshSession(refreshToken string) (*models.AuthResponse, error) {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var tokenID int
+	var sessionID, employeeID, email, roleName, employeeStatus string
+	var usedAt, revokedAt sql.NullTime
+	var expiresAt time.Time
+	err = tx.QueryRow(`
+		SELECT t.token_id, t.session_id, t.used_at, t.expires_at, s.revoked_at,
+		       e.employee_id, e.email, r.role_name, e.status
+		FROM refresh_tokens t
+		JOIN auth_sessions s ON s.session_id = t.session_id
+		JOIN employees e ON e.employee_id = s.employee_id
+		JOIN roles r ON r.role_id = e.role_id
+		WHERE t.token_hash = $1
+		FOR UPDATE OF t, s
+	`, hashOpaqueToken(refreshToken)).Scan(
+		&tokenID, &sessionID, &usedAt, &expiresAt, &revokedAt,
+		&employeeID, &email, &roleName, &employeeStatus,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTokenInvalid
+		}
+		log.Printf("Database error looking up refresh token: %v", err)
+		return nil, errors.New("database error")
+	}
+
+	if revokedAt.Valid {
+		return nil, ErrSessionRevoked
+	}
+
+	if usedAt.Valid {
+		log.Printf("Refresh token reuse detected for session %s (employee %s), revoking session", sessionID, employeeID)
+		if err := revokeSessionTx(tx, sessionID, "refresh token reuse"); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+		return nil, ErrSessionRevoked
+	}
+
+	if time.Now().After(expiresAt) {
+		return nil, ErrTokenExpired
+	}
+
+	if employeeStatus != "Active" {
+		if err := revokeSessionTx(tx, sessionID, "employee inactive"); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+		return nil, ErrSessionRevoked
+	}
+
+	if _, err := tx.Exec(`UPDATE refresh_tokens SET used_at = CURRENT_TIMESTAMP WHERE token_id = $1`, tokenID); err != nil {
+		return nil, err
+	}
+
+	newExpiresAt := time.Now().Add(refreshTokenTTL())
+	_, err = tx.Exec(`
+		UPDATE auth_sessions SET last_used_at = CURRENT_TIMESTAMP, expires_at = $1
+		WHERE session_id = $2
+	`, newExpiresAt, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	newRefreshToken, err := insertRefreshToken(tx, sessionID, newExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := signAccessToken(employeeID, email, roleName, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &models.AuthResponse{
+		Token:        accessToken,
+		RefreshToken: newRefreshToken,
+		ExpiresIn:    int(tokenExpiry().Seconds()),
+		Role:         roleName,
+		Email:        email,
+	}, nil
+}
+
+func revokeSessionTx(tx *sql.Tx, sessionID, reason string) error {
+	_, err := tx.Exec(`
+		UPDATE auth_sessions SET revoked_at = CURRENT_TIMESTAMP, revoked_reason = $1
+		WHERE session_id = $2 AND revoked_at IS NULL
+	`, reason, sessionID)
+	return err
+}
+
+func RevokeSession(sessionID, reason string) error {
+	_, err := database.DB.Exec(`
+		UPDATE auth_sessions SET revoked_at = CURRENT_TIMESTAMP, revoked_reason = $1
+		WHERE session_id = $2 AND revoked_at IS NULL
+	`, reason, sessionID)
+	if err != nil {
+		log.Printf("Failed to revoke session %s: %v", sessionID, err)
+	}
+	return err
+}
+
+func RevokeAllSessions(employeeID, reason string) (int64, error) {
+	result, err := database.DB.Exec(`
+		UPDATE auth_sessions SET revoked_at = CURRENT_TIMESTAMP, revoked_reason = $1
+		WHERE employee_id = $2 AND revoked_at IS NULL
+	`, reason, employeeID)
+	if err != nil {
+		log.Printf("Failed to revoke sessions for employee %s: %v", employeeID, err)
+		return 0, err
+	}
+
+	revoked, _ := result.RowsAffected()
+	log.Printf("Revoked %d session(s) for employee %s: %s", revoked, employeeID, reason)
+	return revoked, nil
+}
+
+func ValidateSession(sessionID, employeeID string) error {
+	if sessionID == "" {
+		return ErrSessionRevoked
+	}
+
+	var exists bool
+	err := database.DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1
+			FROM auth_sessions s
+			JOIN employees e ON e.employee_id = s.employee_id
+			WHERE s.session_id = $1 AND s.employee_id = $2
+			  AND s.revoked_at IS NULL AND s.expires_at > CURRENT_TIMESTAMP
+			  AND e.status = 'Active'
+		)
+	`, sessionID, employeeID).Scan(&exists)
+	if err != nil {
+		log.Printf("Database error validating session %s: %v", sessionID, err)
+		return err
+	}
+	if !exists {
+		return ErrSessionRevoked
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"mantest/backend/internal/database"
+	"mantest/backend/internal/models"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultSLACheckInterval    = 15 * time.Minute
+	defaultEscalationGraceDays = 1
+)
+
+func slaCheckInterval() time.Duration {
+	if value := os.Getenv("SLA_CHECK_INTERVAL"); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err == nil && interval >= 0 {
+			return interval
+		}
+		log.Printf("Invalid SLA_CHECK_INTERVAL %q, using default %s", value, defaultSLACheckInterval)
+	}
+	return defaultSLACheckInterval
+}
+
+func escalationGraceDays() int {
+	if value := os.Getenv("SLA_ESCALATION_GRACE_DAYS"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err == nil && days >= 0 {
+			return days
+		}
+		log.Printf("Invalid SLA_ESCALATION_GRACE_DAYS %q, using default %d", value, defaultEscalationGraceDays)
+	}
+	return defaultEscalationGraceDays
+}
+
+// StartSLAScheduler checks pending approval steps against their due dates in
+// the background. Overdue approvers are reminded once; once the grace period
+// has also passed their vote is handed to their superior. Setting
+// SLA_CHECK_INTERVAL to 0 disables the scheduler.
+func StartSLAScheduler() {
+	interval := slaCheckInterval()
+	if interval == 0 {
+		log.Println("SLA scheduler disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			runSLACheck(time.Now())
+			<-ticker.C
+		}
+	}()
+}
+
+type overdueStepRef struct {
+	requestID int
+	stepID    int
+}
+
+func runSLACheck(now time.Time) {
+	rows, err := database.DB.Query(`
+		SELECT request_id, step_id FROM request_approval_steps
+		WHERE status = $1 AND due_at IS NOT NULL AND due_at <= $2
+		ORDER BY due_at ASC
+	`, models.StepStatusPending, now)
+	if err != nil {
+		log.Printf("SLA check: failed to query overdue steps: %v", err)
+		return
+	}
+
+	var overdue []overdueStepRef
+	for rows.Next() {
+		var ref overdueStepRef
+		if err := rows.Scan(&ref.requestID, &ref.stepID); err != nil {
+			log.Printf("SLA check: failed to scan overdue step: %v", err)
+			rows.Close()
+			return
+		}
+		overdue = append(overdue, ref)
+	}
+	rows.Close()
+
+	for _, ref := range overdue {
+		if err := processOverdueStep(ref.requestID, ref.stepID, now); err != nil {
+			log.Printf("SLA check: failed to process step %d of request %d: %v", ref.stepID, ref.requestID, err)
+		}
+	}
+}
+
+func processOverdueStep(requestID, stepID int, now time.Time) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Lock in the same order as DecideRequest so a concurrent decision and
+	// escalation cannot both act on the same step.
+	var requesterID string
+	err = tx.QueryRow(`SELECT employee_id FROM manpower_requests WHERE request_id = $1 FOR UPDATE`, requestID).Scan(&requesterID)
+	if err != nil {
+		return err
+	}
+
+	var stepName string
+	var slaDays sql.NullInt64
+	var dueAt time.Time
+	var reminded bool
+	err = tx.QueryRow(`
+		SELECT step_name, sla_business_days, due_at, reminder_sent_at IS NOT NULL
+		FROM request_approval_steps
+		WHERE step_id = $1 AND status = $2 AND due_at <= $3
+		FOR UPDATE
+	`, stepID, models.StepStatusPending, now).Scan(&stepName, &slaDays, &dueAt, &reminded)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Decided or escalated since the scan.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	approverIDs, err := queryEmployeeIDs(tx, `
+		SELECT approver_id FROM request_step_approvers
+		WHERE step_id = $1 AND status = $2
+		ORDER BY approver_id ASC
+	`, stepID, models.StepStatusPending)
+	if err != nil {
+		return err
+	}
+
+	if !now.Before(AddBusinessDays(dueAt, escalationGraceDays())) {
+		err = escalateStep(tx, requestID, stepID, stepName, requesterID, approverIDs, slaDays, now)
+	} else if !reminded {
+		err = remindStepApprovers(tx, requestID, stepID, stepName, approverIDs, now)
+	} else {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func remindStepApprovers(tx *sql.Tx, requestID, stepID int, stepName string, approverIDs []string, now time.Time) error {
+	message := fmt.Sprintf("คำขอกำลังพลเลขที่ %d ขั้นตอน %s เกินกำหนดพิจารณาแล้ว", requestID, stepName)
+	for _, approverID := range approverIDs {
+		if err := createNotification(tx, approverID, requestID, models.NotificationSLAReminder, message); err != nil {
+			return err
+		}
+	}
+
+	_, err := tx.Exec(`UPDATE request_approval_steps SET reminder_sent_at = $1 WHERE step_id = $2`, now, stepID)
+	if err != nil {
+		return err
+	}
+	log.Printf("SLA reminder sent for step %d of request %d to %d approver(s)", stepID, requestID, len(approverIDs))
+	return nil
+}
+
+// findSuperior returns the employee an overdue approver escalates to: their
+// supervisor, or failing that their department manager. It returns "" when
+// there is nobody above them.
+func findSuperior(tx *sql.Tx, employeeID string) (string, error) {
+	var superiorID string
+	err := tx.QueryRow(`
+		SELECT s.employee_id
+		FROM employees e
+		LEFT JOIN departments d ON d.dept_id = e.dept_id
+		JOIN employees s ON s.employee_id = COALESCE(e.supervisor_id, NULLIF(d.manager_id, e.employee_id))
+		WHERE e.employee_id = $1 AND s.status = 'Active'
+	`, employeeID).Scan(&superiorID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return superiorID, err
+}
+
+func escalateStep(tx *sql.Tx, requestID, stepID int, stepName, requesterID string, approverIDs []string, slaDays sql.NullInt64, now time.Time) error {
+	escalated := 0
+	for _, approverID := range approverIDs {
+		superiorID, err := findSuperior(tx, approverID)
+		if err != nil {
+			return err
+		}
+		if superiorID == "" || superiorID == requesterID {
+			log.Printf("SLA escalation: no superior to take over from %s on step %d", approverID, stepID)
+			continue
+		}
+
+		var alreadyAssigned bool
+		err = tx.QueryRow(`
+			SELECT EXISTS (SELECT 1 FROM request_step_approvers WHERE step_id = $1 AND approver_id = $2)
+		`, stepID, superiorID).Scan(&alreadyAssigned)
+		if err != nil {
+			return err
+		}
+		if alreadyAssigned {
+			log.Printf("SLA escalation: %s is already an approver on step %d", superiorID, stepID)
+			continue
+		}
+
+		_, err = tx.Exec(`
+			UPDATE request_step_approvers SET status = $1, decided_at = $2
+			WHERE step_id = $3 AND approver_id = $4
+		`, models.StepStatusEscalated, now, stepID, approverID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`
+			INSERT INTO request_step_approvers (step_id, approver_id, status)
+			VALUES ($1, $2, $3)
+		`, stepID, superiorID, models.StepStatusPending)
+		if err != nil {
+			return err
+		}
+
+		notes := fmt.Sprintf("เกินกำหนดพิจารณา ส่งต่อให้ %s พิจารณาแทน", superiorID)
+		if err := recordApprovalHistory(tx, requestID, &stepID, approverID, "", models.DecisionEscalated, notes, stepName); err != nil {
+			return err
+		}
+		message := fmt.Sprintf("คำขอกำลังพลเลขที่ %d ขั้นตอน %s ถูกส่งต่อให้คุณพิจารณาแทน %s", requestID, stepName, approverID)
+		if err := createNotification(tx, superiorID, requestID, models.NotificationEscalation, message); err != nil {
+			return err
+		}
+		escalated++
+	}
+
+	// Restart the clock either way so the step is not re-processed on every
+	// tick; if nobody could take over, the same approvers are reminded again.
+	days := 1
+	if slaDays.Valid && slaDays.Int64 > 0 {
+		days = int(slaDays.Int64)
+	}
+	levelIncrement := 0
+	if escalated > 0 {
+		levelIncrement = 1
+	}
+	_, err := tx.Exec(`
+		UPDATE request_approval_steps
+		SET due_at = $1, reminder_sent_at = NULL, escalation_level = escalation_level + $2
+		WHERE step_id = $3
+	`, AddBusinessDays(now, days), levelIncrement, stepID)
+	if err != nil {
+		return err
+	}
+	log.Printf("SLA escalation for step %d of request %d: %d approver(s) escalated", stepID, requestID, escalated)
+	return nil
+}
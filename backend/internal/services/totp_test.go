@@ -0,0 +1,71 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA-1 key of the RFC 6238 test vectors, base32 encoded.
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	// The last six digits of the RFC 6238 SHA-1 vectors.
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tt := range tests {
+		code, err := totpCode(rfc6238Secret, tt.unix/totpPeriod)
+		if err != nil {
+			t.Fatalf("totpCode(%d): %v", tt.unix, err)
+		}
+		if code != tt.code {
+			t.Errorf("totpCode(%d) = %s, want %s", tt.unix, code, tt.code)
+		}
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	step := now.Unix() / totpPeriod
+	codeAt := func(offset int64) string {
+		code, err := totpCode(rfc6238Secret, step+offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+		ok     bool
+		step   int64
+	}{
+		{"current step", rfc6238Secret, codeAt(0), true, step},
+		{"previous step", rfc6238Secret, codeAt(-1), true, step - 1},
+		{"next step", rfc6238Secret, codeAt(1), true, step + 1},
+		{"surrounding spaces", rfc6238Secret, " " + codeAt(0) + " ", true, step},
+		{"lower-case secret", "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", codeAt(0), true, step},
+		{"two steps old", rfc6238Secret, codeAt(-2), false, 0},
+		{"two steps ahead", rfc6238Secret, codeAt(2), false, 0},
+		{"too short", rfc6238Secret, codeAt(0)[:5], false, 0},
+		{"too long", rfc6238Secret, codeAt(0) + "0", false, 0},
+		{"invalid secret", "not base32!", "123456", false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched, ok := verifyTOTP(tt.secret, tt.code, now)
+			if ok != tt.ok || matched != tt.step {
+				t.Errorf("verifyTOTP() = (%d, %v), want (%d, %v)", matched, ok, tt.step, tt.ok)
+			}
+		})
+	}
+}
@@ -5,31 +5,67 @@ import (
 	"errors"
 	"mantest/backend/internal/database"
 	"mantest/backend/internal/models"
+	"sort"
 )
 
-func GetUserProfileByEmail(email string) (*models.UserProfile, error) {
-	var profile models.UserProfile
+func GetCurrentUser(employeeID string) (*models.CurrentUser, error) {
+	var user models.CurrentUser
+	var roleName, deptName, posName, sectionName, profileImage sql.NullString
 
 	query := `
-        SELECT e.first_name, e.last_name, e.email, r.role_name, d.dept_name
+        SELECT e.employee_id, e.first_name, e.last_name, e.email, r.role_name,
+               d.dept_name, p.pos_name, s.section_name, e.profile_image
         FROM employees e
         LEFT JOIN roles r ON e.role_id = r.role_id
         LEFT JOIN departments d ON e.dept_id = d.dept_id
-        WHERE e.email = $1
+        LEFT JOIN positions p ON e.pos_id = p.pos_id
+        LEFT JOIN sections s ON e.section_id = s.section_id
+        WHERE e.employee_id = $1 AND e.status = 'Active'
     `
-	err := database.DB.QueryRow(query, email).Scan(
-		&profile.FirstName,
-		&profile.LastName,
-		&profile.Email,
-		&profile.Role,
-		&profile.Department,
+	err := database.DB.QueryRow(query, employeeID).Scan(
+		&user.EmployeeID,
+		&user.FirstName,
+		&user.LastName,
+		&user.Email,
+		&roleName,
+		&deptName,
+		&posName,
+		&sectionName,
+		&profileImage,
 	)
-
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, errors.New("user not found")
+			return nil, ErrEmployeeNotFound
 		}
 		return nil, err
 	}
-	return &profile, nil
-}
\ No newline at end of file
+
+	user.Role = roleName.String
+	user.Department = deptName.String
+	user.Position = posName.String
+	user.Section = sectionName.String
+	user.ProfileImageURL = profileImage.String
+
+	permissions, err := GetPermissionsByRole(user.Role)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(permissions)
+	user.Permissions = permissions
+
+	return &user, nil
+}
+
+var ErrEmployeeNotFound = errors.New("employee not found")
+
+func IsEmployeeActive(employeeID string) (bool, error) {
+	var status string
+	err := database.DB.QueryRow(`SELECT status FROM employees WHERE employee_id = $1`, employeeID).Scan(&status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, ErrEmployeeNotFound
+		}
+		return false, err
+	}
+	return status == "Active", nil
+}
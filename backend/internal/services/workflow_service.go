@@ -0,0 +1,814 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"mantest/backend/internal/database"
+	"mantest/backend/internal/models"
+	"strings"
+	"time"
+)
+
+const submissionStepName = "ยื่นคำร้อง"
+
+var (
+	ErrRequestNotFound  = errors.New("manpower request not found")
+	ErrStepNotPending   = errors.New("approval step is not pending")
+	ErrNotStepApprover  = errors.New("you are not the approver for the current step")
+	ErrAlreadyDecided   = errors.New("you have already decided on the current step")
+	ErrOwnRequest       = errors.New("you cannot decide on your own request")
+	ErrNoStepDefinition = errors.New("no approval steps are configured")
+)
+
+type dbQuerier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+type stepDefinition struct {
+	code              string
+	name              string
+	order             int
+	pendingStatus     string
+	approverType      string
+	deptID            sql.NullInt64
+	posID             sql.NullInt64
+	employeeID        sql.NullString
+	isDefault         bool
+	completionPolicy  string
+	requiredApprovals sql.NullInt64
+	slaBusinessDays   sql.NullInt64
+}
+
+type workflowRequest struct {
+	requestID        int
+	requesterID      string
+	deptID           int
+	employmentTypeID int
+	contractTypeID   int
+	reasonID         int
+	headcount        int
+}
+
+type plannedStep struct {
+	def         stepDefinition
+	approverIDs []string
+	required    int
+	skipped     bool
+	skipReason  string
+}
+
+// Reasons recorded when a step is skipped while planning.
+const (
+	skipReasonNoApprover    = "ไม่มีผู้อนุมัติที่ใช้งานอยู่สำหรับขั้นตอนนี้"
+	skipReasonRequesterOnly = "ผู้ร้องขอเป็นผู้อนุมัติเพียงคนเดียวของขั้นตอนนี้"
+)
+
+func loadStepDefinitions(q dbQuerier) ([]stepDefinition, error) {
+	rows, err := q.Query(`
+		SELECT step_code, step_name, step_order, pending_status, approver_type,
+		       approver_dept_id, approver_pos_id, approver_employee_id, is_default,
+		       completion_policy, required_approvals, sla_business_days
+		FROM workflow_step_definitions
+		WHERE status = 'Active'
+		ORDER BY step_order ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var definitions []stepDefinition
+	for rows.Next() {
+		var d stepDefinition
+		if err := rows.Scan(
+			&d.code, &d.name, &d.order, &d.pendingStatus, &d.approverType, &d.deptID, &d.posID, &d.employeeID,
+			&d.isDefault, &d.completionPolicy, &d.requiredApprovals, &d.slaBusinessDays,
+		); err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, d)
+	}
+	return definitions, rows.Err()
+}
+
+func queryEmployeeIDs(q dbQuerier, query string, args ...interface{}) ([]string, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// resolveStepApprovers returns every active employee assigned to the step.
+// POSITION and GROUP steps may resolve to several approvers. A department
+// without an active manager falls back to the requester's supervisor. The
+// result is empty when nobody can approve the step.
+func resolveStepApprovers(q dbQuerier, def stepDefinition, req workflowRequest) ([]string, error) {
+	var approverIDs []string
+	var err error
+
+	switch def.approverType {
+	case "DEPT_MANAGER":
+		approverIDs, err = queryEmployeeIDs(q, `
+			SELECT e.employee_id
+			FROM departments d
+			JOIN employees e ON e.employee_id = d.manager_id
+			WHERE d.dept_id = $1 AND e.status = 'Active'
+		`, req.deptID)
+		if err == nil && len(approverIDs) == 0 {
+			approverIDs, err = queryEmployeeIDs(q, `
+				SELECT s.employee_id
+				FROM employees r
+				JOIN employees s ON s.employee_id = r.supervisor_id
+				WHERE r.employee_id = $1 AND s.status = 'Active'
+			`, req.requesterID)
+		}
+	case "POSITION":
+		approverIDs, err = queryEmployeeIDs(q, `
+			SELECT employee_id FROM employees
+			WHERE dept_id = $1 AND pos_id = $2 AND status = 'Active'
+			ORDER BY employee_id ASC
+		`, def.deptID, def.posID)
+	case "EMPLOYEE":
+		approverIDs, err = queryEmployeeIDs(q, `
+			SELECT employee_id FROM employees WHERE employee_id = $1 AND status = 'Active'
+		`, def.employeeID)
+	case "GROUP":
+		approverIDs, err = queryEmployeeIDs(q, `
+			SELECT e.employee_id
+			FROM workflow_step_group_members m
+			JOIN employees e ON e.employee_id = m.employee_id
+			WHERE m.step_code = $1 AND e.status = 'Active'
+			ORDER BY e.employee_id ASC
+		`, def.code)
+	default:
+		return nil, fmt.Errorf("unknown approver type %q for step %s", def.approverType, def.code)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return approverIDs, nil
+}
+
+// requiredApprovals returns how many approvals the step's completion policy
+// needs out of the given number of approvers. A quorum larger than the
+// approvers left, e.g. once the requester is taken out of the group, is
+// lowered to all of them.
+func requiredApprovals(def stepDefinition, approverCount int) (int, error) {
+	switch def.completionPolicy {
+	case models.CompletionPolicyAll:
+		return approverCount, nil
+	case models.CompletionPolicyAny:
+		return 1, nil
+	case models.CompletionPolicyQuorum:
+		if !def.requiredApprovals.Valid || def.requiredApprovals.Int64 < 1 {
+			return 0, fmt.Errorf("step %s has a quorum policy without a required approval count", def.code)
+		}
+		required := int(def.requiredApprovals.Int64)
+		if required > approverCount {
+			log.Printf("Step %s needs %d approvals but only %d approvers are available; requiring all of them", def.code, required, approverCount)
+			required = approverCount
+		}
+		return required, nil
+	default:
+		return 0, fmt.Errorf("unknown completion policy %q for step %s", def.completionPolicy, def.code)
+	}
+}
+
+// recordApprovalHistory writes one history row. approverID is whoever acted;
+// onBehalfOf is the assigned approver when a delegate acted in their place.
+func recordApprovalHistory(tx *sql.Tx, requestID int, stepID *int, approverID, onBehalfOf, decision, notes, stepName string) error {
+	var sqlStepID sql.NullInt64
+	if stepID != nil {
+		sqlStepID = sql.NullInt64{Int64: int64(*stepID), Valid: true}
+	}
+	var sqlNotes sql.NullString
+	if notes != "" {
+		sqlNotes = sql.NullString{String: notes, Valid: true}
+	}
+	var sqlOnBehalfOf sql.NullString
+	if onBehalfOf != "" {
+		sqlOnBehalfOf = sql.NullString{String: onBehalfOf, Valid: true}
+	}
+
+	_, err := tx.Exec(`
+		INSERT INTO approval_history (request_id, step_id, approver_id, on_behalf_of, decision, notes, step_name)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, requestID, sqlStepID, approverID, sqlOnBehalfOf, decision, sqlNotes, stepName)
+	return err
+}
+
+func setRequestStatus(tx *sql.Tx, requestID int, status string) error {
+	_, err := tx.Exec(`
+		UPDATE manpower_requests SET current_status = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE request_id = $2
+	`, status, requestID)
+	return err
+}
+
+// planWorkflow applies the routing rules to the step definitions and
+// resolves the approvers for every step that remains. The requester is never
+// asked to approve their own request; a step left with nobody else, or with
+// nobody at all, is skipped.
+func planWorkflow(q dbQuerier, req workflowRequest) ([]plannedStep, []models.RoutingRule, error) {
+	definitions, err := loadStepDefinitions(q)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rules, err := matchRoutingRules(q, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	included := make(map[string]bool)
+	for _, def := range definitions {
+		included[def.code] = def.isDefault
+	}
+	for _, rule := range rules {
+		switch rule.Action {
+		case models.RoutingActionAddStep:
+			included[rule.StepCode] = true
+		case models.RoutingActionSkipStep:
+			included[rule.StepCode] = false
+		}
+	}
+
+	var plan []plannedStep
+	for _, def := range definitions {
+		if !included[def.code] {
+			continue
+		}
+		resolved, err := resolveStepApprovers(q, def, req)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		step := plannedStep{def: def}
+		for _, id := range resolved {
+			if id != req.requesterID {
+				step.approverIDs = append(step.approverIDs, id)
+			}
+		}
+		if len(resolved) == 0 {
+			step.skipped = true
+			step.skipReason = skipReasonNoApprover
+		} else if len(step.approverIDs) == 0 {
+			step.approverIDs = resolved
+			step.skipped = true
+			step.skipReason = skipReasonRequesterOnly
+		} else if step.required, err = requiredApprovals(def, len(step.approverIDs)); err != nil {
+			return nil, nil, err
+		}
+		plan = append(plan, step)
+	}
+	if len(plan) == 0 {
+		return nil, nil, ErrNoStepDefinition
+	}
+	return plan, rules, nil
+}
+
+func loadWorkflowRequest(q dbQuerier, requestID int) (workflowRequest, error) {
+	var req workflowRequest
+	err := q.QueryRow(`
+		SELECT request_id, employee_id, requesting_dept_id, employment_type_id,
+		       contract_type_id, reason_id, headcount
+		FROM manpower_requests WHERE request_id = $1
+	`, requestID).Scan(
+		&req.requestID, &req.requesterID, &req.deptID, &req.employmentTypeID,
+		&req.contractTypeID, &req.reasonID, &req.headcount,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return req, ErrRequestNotFound
+	}
+	return req, err
+}
+
+// StartWorkflow builds a new round of the approval chain for a submitted
+// request and activates its first step. It must run in the same transaction
+// as the submission. Earlier rounds, from before the request was returned,
+// are kept for the record.
+func StartWorkflow(tx *sql.Tx, requestID int) error {
+	req, err := loadWorkflowRequest(tx, requestID)
+	if err != nil {
+		return err
+	}
+
+	plan, _, err := planWorkflow(tx, req)
+	if err != nil {
+		return err
+	}
+
+	var round int
+	err = tx.QueryRow(`
+		SELECT COALESCE(MAX(round), 0) + 1 FROM request_approval_steps WHERE request_id = $1
+	`, requestID).Scan(&round)
+	if err != nil {
+		return err
+	}
+
+	if err := recordApprovalHistory(tx, requestID, nil, req.requesterID, "", models.DecisionSubmitted, "", submissionStepName); err != nil {
+		return err
+	}
+
+	for _, step := range plan {
+		status := models.StepStatusWaiting
+		approverStatus := models.StepStatusPending
+		if step.skipped {
+			status = models.StepStatusSkipped
+			approverStatus = models.StepStatusSkipped
+		}
+
+		var stepID int
+		err = tx.QueryRow(`
+			INSERT INTO request_approval_steps (request_id, round, step_order, step_code, step_name, pending_status, completion_policy, required_approvals, sla_business_days, status)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING step_id
+		`, requestID, round, step.def.order, step.def.code, step.def.name, step.def.pendingStatus,
+			step.def.completionPolicy, step.required, step.def.slaBusinessDays, status,
+		).Scan(&stepID)
+		if err != nil {
+			return err
+		}
+
+		for _, approverID := range step.approverIDs {
+			_, err = tx.Exec(`
+				INSERT INTO request_step_approvers (step_id, approver_id, status)
+				VALUES ($1, $2, $3)
+			`, stepID, approverID, approverStatus)
+			if err != nil {
+				return err
+			}
+		}
+
+		if step.skipped {
+			if err := recordApprovalHistory(tx, requestID, &stepID, req.requesterID, "", models.DecisionSkipped, step.skipReason, step.def.name); err != nil {
+				return err
+			}
+		}
+	}
+	if err := transitionRequest(tx, requestID, models.RequestInApproval, "", ""); err != nil {
+		return err
+	}
+
+	return activateNextStep(tx, requestID, "")
+}
+
+// activateNextStep moves the lowest waiting step to Pending and starts its
+// SLA clock, or marks the request approved when no steps remain. actorID is
+// the approver whose decision completed the previous step, if any.
+func activateNextStep(tx *sql.Tx, requestID int, actorID string) error {
+	var stepID int
+	var pendingStatus string
+	var slaDays sql.NullInt64
+	err := tx.QueryRow(`
+		SELECT step_id, pending_status, sla_business_days FROM request_approval_steps
+		WHERE request_id = $1 AND status = $2
+		ORDER BY step_order ASC
+		LIMIT 1
+	`, requestID, models.StepStatusWaiting).Scan(&stepID, &pendingStatus, &slaDays)
+	if errors.Is(err, sql.ErrNoRows) {
+		if err := transitionRequest(tx, requestID, models.RequestApproved, actorID, ""); err != nil {
+			return err
+		}
+		return setRequestStatus(tx, requestID, models.RequestStatusApproved)
+	}
+	if err != nil {
+		return err
+	}
+
+	var dueAt sql.NullTime
+	if slaDays.Valid {
+		dueAt = sql.NullTime{Time: AddBusinessDays(time.Now(), int(slaDays.Int64)), Valid: true}
+	}
+
+	_, err = tx.Exec(`
+		UPDATE request_approval_steps SET status = $1, started_at = CURRENT_TIMESTAMP, due_at = $2
+		WHERE step_id = $3
+	`, models.StepStatusPending, dueAt, stepID)
+	if err != nil {
+		return err
+	}
+	return setRequestStatus(tx, requestID, pendingStatus)
+}
+
+func skipWaitingSteps(tx *sql.Tx, requestID int) error {
+	_, err := tx.Exec(`
+		UPDATE request_approval_steps SET status = $1
+		WHERE request_id = $2 AND status = $3
+	`, models.StepStatusSkipped, requestID, models.StepStatusWaiting)
+	return err
+}
+
+func completeStep(tx *sql.Tx, stepID int, status string) error {
+	_, err := tx.Exec(`
+		UPDATE request_approval_steps SET status = $1, completed_at = CURRENT_TIMESTAMP
+		WHERE step_id = $2
+	`, status, stepID)
+	if err != nil {
+		return err
+	}
+
+	// Approvers who had not voted when the step resolved no longer need to.
+	_, err = tx.Exec(`
+		UPDATE request_step_approvers SET status = $1
+		WHERE step_id = $2 AND status = $3
+	`, models.StepStatusSkipped, stepID, models.StepStatusPending)
+	return err
+}
+
+// AdvanceWorkflow records an approver's vote on the request's current step
+// and resolves the step once its completion policy is met. A return sends the
+// request back immediately; a rejection only fails the step once the
+// remaining votes can no longer reach the required number of approvals.
+// An active delegate may cast the vote of the approver they stand in for;
+// their own vote, if they have one on the step, is used first. Each person
+// votes at most once per step, however many approvers they stand in for, and
+// never on a request they raised.
+func AdvanceWorkflow(tx *sql.Tx, requestID int, approverID, decision, notes string) error {
+	var stepID, required int
+	var stepName string
+	err := tx.QueryRow(`
+		SELECT step_id, step_name, required_approvals FROM request_approval_steps
+		WHERE request_id = $1 AND status = $2
+		ORDER BY step_order ASC
+		LIMIT 1
+		FOR UPDATE
+	`, requestID, models.StepStatusPending).Scan(&stepID, &stepName, &required)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrStepNotPending
+		}
+		return err
+	}
+
+	var requesterID string
+	if err := tx.QueryRow(`SELECT employee_id FROM manpower_requests WHERE request_id = $1`, requestID).Scan(&requesterID); err != nil {
+		return err
+	}
+	if requesterID == approverID {
+		return ErrOwnRequest
+	}
+
+	var alreadyVoted bool
+	err = tx.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM request_step_approvers
+			WHERE step_id = $1 AND status IN ($3, $4, $5)
+			  AND (acted_by = $2 OR (approver_id = $2 AND acted_by IS NULL))
+		)
+	`, stepID, approverID, models.StepStatusApproved, models.StepStatusRejected, models.StepStatusReturned).Scan(&alreadyVoted)
+	if err != nil {
+		return err
+	}
+	if alreadyVoted {
+		return ErrAlreadyDecided
+	}
+
+	var voteStatus string
+	switch decision {
+	case models.DecisionApproved:
+		voteStatus = models.StepStatusApproved
+	case models.DecisionRejected:
+		voteStatus = models.StepStatusRejected
+	case models.DecisionReturned:
+		voteStatus = models.StepStatusReturned
+	default:
+		return fmt.Errorf("unsupported decision %q", decision)
+	}
+
+	var assignedApprover, currentVote string
+	err = tx.QueryRow(`
+		SELECT approver_id, status FROM request_step_approvers
+		WHERE step_id = $1 AND (
+			approver_id = $2 OR approver_id IN (
+				SELECT d.employee_id FROM approver_delegations d
+				WHERE d.delegate_id = $2 AND `+activeDelegation("d")+`
+			)
+		)
+		ORDER BY (status = $3) DESC, (approver_id = $2) DESC, approver_id ASC
+		LIMIT 1
+		FOR UPDATE
+	`, stepID, approverID, models.StepStatusPending).Scan(&assignedApprover, &currentVote)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotStepApprover
+		}
+		return err
+	}
+	if currentVote != models.StepStatusPending {
+		return ErrAlreadyDecided
+	}
+
+	var onBehalfOf string
+	var actedBy sql.NullString
+	if assignedApprover != approverID {
+		onBehalfOf = assignedApprover
+		actedBy = sql.NullString{String: approverID, Valid: true}
+	}
+
+	_, err = tx.Exec(`
+		UPDATE request_step_approvers SET status = $1, acted_by = $2, decided_at = CURRENT_TIMESTAMP
+		WHERE step_id = $3 AND approver_id = $4
+	`, voteStatus, actedBy, stepID, assignedApprover)
+	if err != nil {
+		return err
+	}
+
+	if err := recordApprovalHistory(tx, requestID, &stepID, approverID, onBehalfOf, decision, notes, stepName); err != nil {
+		return err
+	}
+
+	var approved, undecided int
+	err = tx.QueryRow(`
+		SELECT COUNT(*) FILTER (WHERE status = $2), COUNT(*) FILTER (WHERE status = $3)
+		FROM request_step_approvers WHERE step_id = $1
+	`, stepID, models.StepStatusApproved, models.StepStatusPending).Scan(&approved, &undecided)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case decision == models.DecisionReturned:
+		if err := completeStep(tx, stepID, models.StepStatusReturned); err != nil {
+			return err
+		}
+		// The rest of this round is abandoned; resubmission starts a new one.
+		if err := skipWaitingSteps(tx, requestID); err != nil {
+			return err
+		}
+		if err := transitionRequest(tx, requestID, models.RequestReturned, approverID, notes); err != nil {
+			return err
+		}
+		return setRequestStatus(tx, requestID, models.RequestStatusReturned)
+
+	case approved >= required:
+		if err := completeStep(tx, stepID, models.StepStatusApproved); err != nil {
+			return err
+		}
+		return activateNextStep(tx, requestID, approverID)
+
+	case approved+undecided < required:
+		if err := completeStep(tx, stepID, models.StepStatusRejected); err != nil {
+			return err
+		}
+		if err := skipWaitingSteps(tx, requestID); err != nil {
+			return err
+		}
+		if err := transitionRequest(tx, requestID, models.RequestRejected, approverID, notes); err != nil {
+			return err
+		}
+		return setRequestStatus(tx, requestID, models.RequestStatusRejected)
+	}
+
+	// The policy is not decided yet; the step stays pending for the others.
+	return nil
+}
+
+func getStepApprovers(requestID int) (map[int][]models.StepApprover, error) {
+	// The delegate shown is whoever acted on the vote, or for an undecided
+	// vote whoever currently stands in for the approver.
+	rows, err := database.DB.Query(`
+		SELECT a.step_id, a.approver_id, COALESCE(e.first_name || ' ' || e.last_name, ''),
+		       a.status, a.decided_at,
+		       COALESCE(a.acted_by, d.delegate_id, ''), COALESCE(de.first_name || ' ' || de.last_name, '')
+		FROM request_step_approvers a
+		JOIN request_approval_steps s ON s.step_id = a.step_id
+		LEFT JOIN employees e ON e.employee_id = a.approver_id
+		LEFT JOIN approver_delegations d ON d.employee_id = a.approver_id
+		     AND a.status = 'Pending' AND `+activeDelegation("d")+`
+		LEFT JOIN employees de ON de.employee_id = COALESCE(a.acted_by, d.delegate_id)
+		WHERE s.request_id = $1
+		ORDER BY a.step_id ASC, a.approver_id ASC
+	`, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	approvers := make(map[int][]models.StepApprover)
+	for rows.Next() {
+		var stepID int
+		var approver models.StepApprover
+		var decidedAt sql.NullTime
+		if err := rows.Scan(
+			&stepID, &approver.ApproverID, &approver.ApproverName, &approver.Status, &decidedAt,
+			&approver.DelegateID, &approver.DelegateName,
+		); err != nil {
+			return nil, err
+		}
+		if decidedAt.Valid {
+			approver.DecidedAt = &decidedAt.Time
+		}
+		approvers[stepID] = append(approvers[stepID], approver)
+	}
+	return approvers, rows.Err()
+}
+
+func GetApprovalSteps(requestID int) ([]models.ApprovalStep, error) {
+	approvers, err := getStepApprovers(requestID)
+	if err != nil {
+		log.Printf("Error querying step approvers for request %d: %v", requestID, err)
+		return nil, err
+	}
+
+	rows, err := database.DB.Query(`
+		SELECT step_id, round, step_order, step_code, step_name, pending_status,
+		       completion_policy, required_approvals, status, started_at, completed_at,
+		       sla_business_days, due_at, escalation_level
+		FROM request_approval_steps
+		WHERE request_id = $1
+		ORDER BY round ASC, step_order ASC
+	`, requestID)
+	if err != nil {
+		log.Printf("Error querying approval steps for request %d: %v", requestID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	steps := []models.ApprovalStep{}
+	for rows.Next() {
+		var step models.ApprovalStep
+		var startedAt, completedAt, dueAt sql.NullTime
+		var slaDays sql.NullInt64
+		err := rows.Scan(
+			&step.StepID,
+			&step.Round,
+			&step.StepOrder,
+			&step.StepCode,
+			&step.StepName,
+			&step.PendingStatus,
+			&step.CompletionPolicy,
+			&step.RequiredApprovals,
+			&step.Status,
+			&startedAt,
+			&completedAt,
+			&slaDays,
+			&dueAt,
+			&step.EscalationLevel,
+		)
+		if err != nil {
+			log.Printf("Error scanning approval step row: %v", err)
+			return nil, err
+		}
+		if startedAt.Valid {
+			step.StartedAt = &startedAt.Time
+		}
+		if completedAt.Valid {
+			step.CompletedAt = &completedAt.Time
+		}
+		if slaDays.Valid {
+			days := int(slaDays.Int64)
+			step.SLABusinessDays = &days
+		}
+		if dueAt.Valid {
+			step.DueAt = &dueAt.Time
+		}
+		step.Approvers = approvers[step.StepID]
+		if step.Approvers == nil {
+			step.Approvers = []models.StepApprover{}
+		}
+		for _, approver := range step.Approvers {
+			if approver.Status == models.StepStatusApproved {
+				step.ApprovalCount++
+			}
+		}
+		steps = append(steps, step)
+	}
+	return steps, rows.Err()
+}
+
+func GetApprovalHistory(requestID int) ([]models.ApprovalHistoryEntry, error) {
+	rows, err := database.DB.Query(`
+		SELECT h.history_id, h.step_id, h.step_name, h.approver_id,
+		       COALESCE(e.first_name || ' ' || e.last_name, ''),
+		       COALESCE(h.on_behalf_of, ''), COALESCE(o.first_name || ' ' || o.last_name, ''),
+		       h.decision, COALESCE(h.notes, ''), h.approval_time
+		FROM approval_history h
+		LEFT JOIN employees e ON e.employee_id = h.approver_id
+		LEFT JOIN employees o ON o.employee_id = h.on_behalf_of
+		WHERE h.request_id = $1 AND h.status = 'Active'
+		ORDER BY h.approval_time ASC, h.history_id ASC
+	`, requestID)
+	if err != nil {
+		log.Printf("Error querying approval history for request %d: %v", requestID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []models.ApprovalHistoryEntry{}
+	for rows.Next() {
+		var entry models.ApprovalHistoryEntry
+		var stepID sql.NullInt64
+		err := rows.Scan(
+			&entry.HistoryID,
+			&stepID,
+			&entry.StepName,
+			&entry.ApproverID,
+			&entry.ApproverName,
+			&entry.OnBehalfOf,
+			&entry.OnBehalfOfName,
+			&entry.Decision,
+			&entry.Notes,
+			&entry.ApprovalTime,
+		)
+		if err != nil {
+			log.Printf("Error scanning approval history row: %v", err)
+			return nil, err
+		}
+		if stepID.Valid {
+			id := int(stepID.Int64)
+			entry.StepID = &id
+		}
+		history = append(history, entry)
+	}
+	return history, rows.Err()
+}
+
+func GetWorkflowState(requestID int) (*models.WorkflowState, error) {
+	state := models.WorkflowState{RequestID: requestID}
+	err := database.DB.QueryRow(`
+		SELECT employee_id, COALESCE(current_status, ''), lifecycle_status FROM manpower_requests WHERE request_id = $1
+	`, requestID).Scan(&state.RequesterID, &state.CurrentStatus, &state.LifecycleStatus)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRequestNotFound
+		}
+		return nil, err
+	}
+
+	if state.Steps, err = GetApprovalSteps(requestID); err != nil {
+		return nil, err
+	}
+	if state.History, err = GetApprovalHistory(requestID); err != nil {
+		return nil, err
+	}
+	if state.Transitions, err = GetRequestTransitions(requestID); err != nil {
+		return nil, err
+	}
+
+	for i := range state.Steps {
+		if state.Steps[i].Status == models.StepStatusPending {
+			state.CurrentStep = &state.Steps[i]
+			var pending []string
+			for _, approver := range state.Steps[i].Approvers {
+				if approver.Status != models.StepStatusPending {
+					continue
+				}
+				if approver.DelegateID != "" {
+					pending = append(pending, approver.DelegateName)
+				} else {
+					pending = append(pending, approver.ApproverName)
+				}
+			}
+			state.PendingApprover = strings.Join(pending, ", ")
+			break
+		}
+	}
+	return &state, nil
+}
+
+// requestVisibility is the SQL condition for a request, aliased r, that the
+// employee bound at param raised, can decide on now, directly or as an
+// active delegate, or has already decided on.
+func requestVisibility(param string) string {
+	return `(r.employee_id = ` + param + ` OR EXISTS (
+		SELECT 1 FROM request_step_approvers a
+		JOIN request_approval_steps s ON s.step_id = a.step_id
+		LEFT JOIN approver_delegations d ON d.employee_id = a.approver_id AND ` + activeDelegation("d") + `
+		WHERE s.request_id = r.request_id
+		  AND (
+			(s.status = '` + models.StepStatusPending + `' AND a.status = '` + models.StepStatusPending + `'
+			 AND (a.approver_id = ` + param + ` OR d.delegate_id = ` + param + `))
+			OR (a.status IN ('` + models.StepStatusApproved + `', '` + models.StepStatusRejected + `', '` + models.StepStatusReturned + `')
+			 AND (a.acted_by = ` + param + ` OR (a.approver_id = ` + param + ` AND a.acted_by IS NULL)))
+		  )
+	))`
+}
+
+// CanViewRequest reports whether the employee is the requester, can decide
+// on the request's current step, or has already decided on one of its steps.
+func CanViewRequest(requestID int, employeeID string) (bool, error) {
+	var allowed bool
+	err := database.DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM manpower_requests r
+			WHERE r.request_id = $1 AND `+requestVisibility("$2")+`
+		)
+	`, requestID, employeeID).Scan(&allowed)
+	return allowed, err
+}
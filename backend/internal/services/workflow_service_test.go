@@ -0,0 +1,45 @@
+package services
+
+import (
+	"database/sql"
+	"mantest/backend/internal/models"
+	"testing"
+)
+
+func TestRequiredApprovals(t *testing.T) {
+	quorum := func(n int64) stepDefinition {
+		return stepDefinition{
+			code:              "BOARD",
+			completionPolicy:  models.CompletionPolicyQuorum,
+			requiredApprovals: sql.NullInt64{Int64: n, Valid: true},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		def       stepDefinition
+		approvers int
+		want      int
+		wantErr   bool
+	}{
+		{"all", stepDefinition{code: "HR", completionPolicy: models.CompletionPolicyAll}, 3, 3, false},
+		{"any", stepDefinition{code: "HR", completionPolicy: models.CompletionPolicyAny}, 3, 1, false},
+		{"quorum within group", quorum(2), 3, 2, false},
+		{"quorum equal to group", quorum(3), 3, 3, false},
+		{"quorum above group is lowered", quorum(3), 2, 2, false},
+		{"quorum without count", stepDefinition{code: "BOARD", completionPolicy: models.CompletionPolicyQuorum}, 3, 0, true},
+		{"quorum of zero", quorum(0), 3, 0, true},
+		{"unknown policy", stepDefinition{code: "HR", completionPolicy: "MAJORITY"}, 3, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := requiredApprovals(tt.def, tt.approvers)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("requiredApprovals() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("requiredApprovals() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package validation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Error codes reported per field. Clients should key off the code; the
+// messages are for display.
+const (
+	CodeRequired      = "REQUIRED"
+	CodeInvalidChoice = "INVALID_CHOICE"
+	CodeInvalidNumber = "INVALID_NUMBER"
+	CodeInvalidDate   = "INVALID_DATE"
+	CodeInvalidType   = "INVALID_TYPE"
+	CodeInvalidFormat = "INVALID_FORMAT"
+	CodeOutOfRange    = "OUT_OF_RANGE"
+	CodeTooSmall      = "TOO_SMALL"
+	CodeTooLarge      = "TOO_LARGE"
+	CodeTooLong       = "TOO_LONG"
+	CodeDateInFuture  = "DATE_IN_FUTURE"
+	CodeDateTooEarly  = "DATE_TOO_EARLY"
+	CodeRangeInverted = "RANGE_INVERTED"
+	CodeNotWithLines  = "CONFLICTS_WITH_LINES"
+	CodeNotWorkingDay = "NOT_WORKING_DAY"
+)
+
+type message struct {
+	en string
+	th string
+}
+
+// messages holds the English and Thai text of each code. The %s verbs are
+// filled with the field name followed by the code's parameters.
+var messages = map[string]message{
+	CodeRequired:      {"%s is required", "กรุณากรอก %s"},
+	CodeInvalidChoice: {"%s: %s is not a valid selection", "%s: ไม่พบตัวเลือก %s"},
+	CodeInvalidNumber: {"%s must be a whole number", "%s ต้องเป็นตัวเลขจำนวนเต็ม"},
+	CodeInvalidDate:   {"%s must be a date in %s format", "%s ต้องเป็นวันที่ในรูปแบบ %s"},
+	CodeInvalidType:   {"%s has the wrong type", "%s มีชนิดข้อมูลไม่ถูกต้อง"},
+	CodeInvalidFormat: {"%s is not in a valid format", "%s มีรูปแบบไม่ถูกต้อง"},
+	CodeOutOfRange:    {"%s must be between %s and %s", "%s ต้องอยู่ระหว่าง %s ถึง %s"},
+	CodeTooSmall:      {"%s must be at least %s", "%s ต้องไม่น้อยกว่า %s"},
+	CodeTooLarge:      {"%s must be at most %s", "%s ต้องไม่มากกว่า %s"},
+	CodeTooLong:       {"%s must be at most %s characters", "%s ต้องมีความยาวไม่เกิน %s ตัวอักษร"},
+	CodeDateInFuture:  {"%s cannot be in the future", "%s ต้องไม่เป็นวันที่ในอนาคต"},
+	CodeDateTooEarly:  {"%s must not be earlier than %s", "%s ต้องไม่ก่อนวันที่ %s"},
+	CodeRangeInverted: {"%s must not be greater than %s", "%s ต้องไม่มากกว่า %s"},
+	CodeNotWithLines:  {"%s cannot be sent together with lines", "ไม่สามารถระบุ %s พร้อมกับรายการตำแหน่ง"},
+	CodeNotWorkingDay: {"%s: %s is a weekend or public holiday", "%s: วันที่ %s เป็นวันหยุด"},
+}
+
+// fieldLabels are the Thai form labels used in Thai messages. Fields not
+// listed fall back to their payload name.
+var fieldLabels = map[string]string{
+	"documentDate":          "วันที่เอกสาร",
+	"department":            "ฝ่าย",
+	"departmentId":          "ฝ่าย",
+	"employmentType":        "ประเภทการจ้าง",
+	"employmentTypeId":      "ประเภทการจ้าง",
+	"contractType":          "ประเภทสัญญาจ้าง",
+	"contractTypeId":        "ประเภทสัญญาจ้าง",
+	"requestReason":         "เหตุผลที่ร้องขอ",
+	"requestReasonId":       "เหตุผลที่ร้องขอ",
+	"positionId":            "รหัสตำแหน่งงาน",
+	"positionRequire":       "ตำแหน่งที่ต้องการ",
+	"positionRequireId":     "ตำแหน่งที่ต้องการ",
+	"ageFrom":               "อายุตั้งแต่",
+	"ageTo":                 "ถึงอายุ",
+	"gender":                "เพศ",
+	"genderId":              "เพศ",
+	"nationality":           "สัญชาติ",
+	"nationalityId":         "สัญชาติ",
+	"experience":            "ประสบการณ์",
+	"experienceId":          "ประสบการณ์",
+	"educationLevel":        "ระดับการศึกษา",
+	"educationLevelId":      "ระดับการศึกษา",
+	"specialQualifications": "คุณสมบัติพิเศษ",
+	"headcount":             "จำนวนที่ต้องการ",
+	"requesterName":         "ชื่อผู้ร้องขอ",
+	"requesterEmployeeId":   "รหัสพนักงานผู้ร้องขอ",
+	"lines":                 "รายการตำแหน่ง",
+	"quantity":              "จำนวน",
+	"qualifications":        "คุณสมบัติเฉพาะตำแหน่ง",
+	"targetHireDate":        "วันที่ต้องการให้เริ่มงาน",
+	"role":                  "บทบาท",
+	"roleId":                "บทบาท",
+	"section":               "แผนก",
+	"sectionId":             "แผนก",
+	"position":              "ตำแหน่ง",
+}
+
+func label(field string) string {
+	if l, ok := fieldLabels[field]; ok {
+		return l
+	}
+	// Fields of list items, e.g. lines[0].quantity, are labelled by the
+	// item's field and its position in the list, counting from one.
+	if open := strings.Index(field, "["); open > 0 {
+		if end := strings.Index(field, "]."); end > open {
+			if n, err := strconv.Atoi(field[open+1 : end]); err == nil {
+				return fmt.Sprintf("%s (รายการที่ %d)", label(field[end+2:]), n+1)
+			}
+		}
+	}
+	return field
+}
+
+func render(format string, subject string, params []string) string {
+	args := make([]interface{}, 0, len(params)+1)
+	args = append(args, subject)
+	for _, p := range params {
+		args = append(args, p)
+	}
+	return fmt.Sprintf(format, args...)
+}
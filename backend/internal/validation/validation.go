@@ -0,0 +1,244 @@
+// Package validation checks client payloads field by field and reports every
+// problem at once in a standard envelope, with a code and English and Thai
+// messages per field.
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mantest/backend/internal/models"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// NewFieldError builds the error for one field. params fill in the message
+// after the field name, e.g. the bounds of CodeOutOfRange.
+func NewFieldError(field, code, value string, params ...string) models.FieldError {
+	msg, ok := messages[code]
+	if !ok {
+		msg = messages[CodeInvalidFormat]
+	}
+	return models.FieldError{
+		Field:     field,
+		Code:      code,
+		Value:     value,
+		Message:   render(msg.en, field, params),
+		MessageTH: render(msg.th, label(field), params),
+	}
+}
+
+// Failure wraps field errors in the envelope every validation failure is
+// answered with.
+func Failure(fields []models.FieldError) models.ValidationErrorResponse {
+	return models.ValidationErrorResponse{
+		Error:  "Validation failed",
+		Code:   "VALIDATION_FAILED",
+		Fields: fields,
+	}
+}
+
+// IntInput is a whole number that clients may send either as a JSON number
+// or as a numeric string, as the request form does. The raw text is kept so
+// a bad value is reported against its field instead of failing the whole
+// payload.
+type IntInput struct {
+	Raw string
+}
+
+func (n *IntInput) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		n.Raw = strings.TrimSpace(s)
+		return nil
+	}
+	if string(data) == "null" {
+		n.Raw = ""
+		return nil
+	}
+	n.Raw = string(data)
+	return nil
+}
+
+func (n IntInput) IsEmpty() bool {
+	return n.Raw == ""
+}
+
+// Validator collects field errors. Each rule returns whether the value passed
+// so callers can skip checks that depend on it.
+type Validator struct {
+	errors []models.FieldError
+}
+
+func (v *Validator) Add(field, code, value string, params ...string) {
+	v.errors = append(v.errors, NewFieldError(field, code, value, params...))
+}
+
+func (v *Validator) Merge(errs []models.FieldError) {
+	v.errors = append(v.errors, errs...)
+}
+
+func (v *Validator) Errors() []models.FieldError {
+	return v.errors
+}
+
+func (v *Validator) Valid() bool {
+	return len(v.errors) == 0
+}
+
+func (v *Validator) Required(field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		v.Add(field, CodeRequired, "")
+		return false
+	}
+	return true
+}
+
+// MaxLength counts characters, not bytes, so Thai text gets the full limit.
+func (v *Validator) MaxLength(field, value string, max int) bool {
+	if utf8.RuneCountInString(value) > max {
+		v.Add(field, CodeTooLong, "", strconv.Itoa(max))
+		return false
+	}
+	return true
+}
+
+// IntRange parses a whole number and checks it lies within [min, max].
+func (v *Validator) IntRange(field string, in IntInput, min, max int) (int, bool) {
+	n, err := strconv.Atoi(in.Raw)
+	if err != nil {
+		v.Add(field, CodeInvalidNumber, in.Raw)
+		return 0, false
+	}
+	return n, v.Between(field, n, min, max)
+}
+
+func (v *Validator) Between(field string, n, min, max int) bool {
+	if n < min || n > max {
+		v.Add(field, CodeOutOfRange, strconv.Itoa(n), strconv.Itoa(min), strconv.Itoa(max))
+		return false
+	}
+	return true
+}
+
+// Date parses value with layout; display is the layout as shown to users.
+func (v *Validator) Date(field, value, layout, display string) (time.Time, bool) {
+	t, err := time.Parse(layout, value)
+	if err != nil {
+		v.Add(field, CodeInvalidDate, value, display)
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// NotAfter reports a calendar date later than today's. Both are compared by
+// their date only.
+func (v *Validator) NotAfter(field string, date, today time.Time) bool {
+	if date.Format("2006-01-02") > today.Format("2006-01-02") {
+		v.Add(field, CodeDateInFuture, date.Format("02/01/2006"))
+		return false
+	}
+	return true
+}
+
+// NotBefore reports a calendar date earlier than earliest, comparing dates
+// only.
+func (v *Validator) NotBefore(field string, date, earliest time.Time) bool {
+	if date.Format("2006-01-02") < earliest.Format("2006-01-02") {
+		v.Add(field, CodeDateTooEarly, date.Format("02/01/2006"), earliest.Format("02/01/2006"))
+		return false
+	}
+	return true
+}
+
+// Ordered reports lower being greater than upper. The error is attached to
+// the upper field, with the lower field named in the message.
+func (v *Validator) Ordered(lowerField string, lower int, upperField string, upper int) bool {
+	if lower > upper {
+		v.errors = append(v.errors, models.FieldError{
+			Field:     upperField,
+			Code:      CodeRangeInverted,
+			Value:     strconv.Itoa(upper),
+			Message:   render(messages[CodeRangeInverted].en, lowerField, []string{upperField}),
+			MessageTH: render(messages[CodeRangeInverted].th, label(lowerField), []string{label(upperField)}),
+		})
+		return false
+	}
+	return true
+}
+
+// UseJSONFieldNames makes binding-tag failures name fields by their JSON
+// key, the name clients know them by.
+func UseJSONFieldNames() {
+	engine, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	engine.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			return field.Name
+		}
+		return name
+	})
+}
+
+// FromBindError turns a failed ShouldBindJSON into field errors. Type
+// mismatches and binding-tag failures are reported per field; anything else,
+// such as malformed JSON, is reported against the payload as a whole.
+func FromBindError(err error) []models.FieldError {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return []models.FieldError{NewFieldError(typeErr.Field, CodeInvalidType, "")}
+	}
+
+	var tagErrs validator.ValidationErrors
+	if !errors.As(err, &tagErrs) {
+		return []models.FieldError{{
+			Code:      CodeInvalidFormat,
+			Message:   "Invalid request payload: " + err.Error(),
+			MessageTH: "รูปแบบข้อมูลที่ส่งมาไม่ถูกต้อง",
+		}}
+	}
+
+	fields := make([]models.FieldError, 0, len(tagErrs))
+	for _, fe := range tagErrs {
+		value := fmt.Sprint(fe.Value())
+		field := fieldPath(fe)
+		switch fe.Tag() {
+		case "required":
+			fields = append(fields, NewFieldError(field, CodeRequired, ""))
+		case "max":
+			if fe.Kind() == reflect.String {
+				fields = append(fields, NewFieldError(field, CodeTooLong, "", fe.Param()))
+			} else {
+				fields = append(fields, NewFieldError(field, CodeTooLarge, value, fe.Param()))
+			}
+		case "min":
+			fields = append(fields, NewFieldError(field, CodeTooSmall, value, fe.Param()))
+		case "oneof":
+			fields = append(fields, NewFieldError(field, CodeInvalidChoice, value, value))
+		case "datetime":
+			fields = append(fields, NewFieldError(field, CodeInvalidDate, value, fe.Param()))
+		default:
+			fields = append(fields, NewFieldError(field, CodeInvalidFormat, value))
+		}
+	}
+	return fields
+}
+
+// fieldPath names a failed field by its path below the payload, such as
+// lines[0].quantity for a field of a list item.
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.Index(ns, "."); i >= 0 {
+		return ns[i+1:]
+	}
+	return fe.Field()
+}
@@ -0,0 +1,173 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+func TestLabel(t *testing.T) {
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{"department", "ฝ่าย"},
+		{"quantity", "จำนวน"},
+		{"lines[0].quantity", "จำนวน (รายการที่ 1)"},
+		{"lines[11].targetHireDate", "วันที่ต้องการให้เริ่มงาน (รายการที่ 12)"},
+		{"unknownField", "unknownField"},
+		{"lines[x].quantity", "lines[x].quantity"},
+		{"lines[0]", "lines[0]"},
+	}
+	for _, tt := range tests {
+		if got := label(tt.field); got != tt.want {
+			t.Errorf("label(%q) = %q, want %q", tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestIntInputUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		json  string
+		raw   string
+		empty bool
+	}{
+		{`25`, "25", false},
+		{`"25"`, "25", false},
+		{`" 30 "`, "30", false},
+		{`""`, "", true},
+		{`null`, "", true},
+		{`"abc"`, "abc", false},
+		{`2.5`, "2.5", false},
+		{`true`, "true", false},
+	}
+	for _, tt := range tests {
+		var payload struct {
+			Age IntInput `json:"age"`
+		}
+		if err := json.Unmarshal([]byte(`{"age":`+tt.json+`}`), &payload); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", tt.json, err)
+		}
+		if payload.Age.Raw != tt.raw || payload.Age.IsEmpty() != tt.empty {
+			t.Errorf("Unmarshal(%s) = %q (empty %v), want %q (empty %v)", tt.json, payload.Age.Raw, payload.Age.IsEmpty(), tt.raw, tt.empty)
+		}
+	}
+}
+
+func TestIntRange(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want int
+		ok   bool
+		code string
+	}{
+		{"18", 18, true, ""},
+		{"15", 15, true, ""},
+		{"70", 70, true, ""},
+		{"14", 14, false, CodeOutOfRange},
+		{"abc", 0, false, CodeInvalidNumber},
+		{"2.5", 0, false, CodeInvalidNumber},
+	}
+	for _, tt := range tests {
+		var v Validator
+		got, ok := v.IntRange("ageFrom", IntInput{Raw: tt.raw}, 15, 70)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("IntRange(%q) = (%d, %v), want (%d, %v)", tt.raw, got, ok, tt.want, tt.ok)
+		}
+		if tt.code == "" {
+			if !v.Valid() {
+				t.Errorf("IntRange(%q) reported %v", tt.raw, v.Errors())
+			}
+			continue
+		}
+		if errs := v.Errors(); len(errs) != 1 || errs[0].Code != tt.code || errs[0].Field != "ageFrom" {
+			t.Errorf("IntRange(%q) errors = %v, want one %s on ageFrom", tt.raw, errs, tt.code)
+		}
+	}
+}
+
+type bindLine struct {
+	Quantity int    `json:"quantity" binding:"min=1,max=999"`
+	Name     string `json:"name" binding:"max=5"`
+}
+
+type bindPayload struct {
+	Email  string     `json:"email" binding:"required"`
+	Status string     `json:"status" binding:"omitempty,oneof=Active Inactive"`
+	Lines  []bindLine `json:"lines" binding:"dive"`
+}
+
+func TestFromBindError(t *testing.T) {
+	UseJSONFieldNames()
+
+	type want struct {
+		field, code, value string
+	}
+	tests := []struct {
+		name    string
+		payload bindPayload
+		want    []want
+	}{
+		{
+			name:    "required",
+			payload: bindPayload{},
+			want:    []want{{"email", CodeRequired, ""}},
+		},
+		{
+			name:    "choice",
+			payload: bindPayload{Email: "a@b.c", Status: "Gone"},
+			want:    []want{{"status", CodeInvalidChoice, "Gone"}},
+		},
+		{
+			name: "list items",
+			payload: bindPayload{Email: "a@b.c", Lines: []bindLine{
+				{Quantity: 1, Name: "ok"},
+				{Quantity: 0, Name: "toolong"},
+				{Quantity: 1000},
+			}},
+			want: []want{
+				{"lines[1].quantity", CodeTooSmall, "0"},
+				{"lines[1].name", CodeTooLong, ""},
+				{"lines[2].quantity", CodeTooLarge, "1000"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := binding.Validator.ValidateStruct(&tt.payload)
+			if err == nil {
+				t.Fatal("ValidateStruct() passed")
+			}
+			got := FromBindError(err)
+			if len(got) != len(tt.want) {
+				t.Fatalf("FromBindError() = %v, want %d errors", got, len(tt.want))
+			}
+			for i, w := range tt.want {
+				if got[i].Field != w.field || got[i].Code != w.code || got[i].Value != w.value {
+					t.Errorf("error %d = %s/%s/%q, want %s/%s/%q", i, got[i].Field, got[i].Code, got[i].Value, w.field, w.code, w.value)
+				}
+				if got[i].Message == "" || got[i].MessageTH == "" {
+					t.Errorf("error %d has no message: %+v", i, got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFromBindErrorDecoding(t *testing.T) {
+	var payload struct {
+		Quantity int `json:"quantity"`
+	}
+	typeErr := json.Unmarshal([]byte(`{"quantity":"three"}`), &payload)
+	got := FromBindError(typeErr)
+	if len(got) != 1 || got[0].Field != "quantity" || got[0].Code != CodeInvalidType {
+		t.Errorf("FromBindError(type error) = %v", got)
+	}
+
+	got = FromBindError(errors.New("unexpected EOF"))
+	if len(got) != 1 || got[0].Field != "" || got[0].Code != CodeInvalidFormat {
+		t.Errorf("FromBindError(syntax error) = %v", got)
+	}
+}
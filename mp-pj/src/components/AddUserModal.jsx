@@ -1,4 +1,5 @@
 import React, { useState, useEffect } from 'react';
+import authFetch from '../utils/authFetch';
 
 const INITIAL_FORM_STATE = {
   role: '',
@@ -22,7 +23,7 @@ function AddUserModal({ isOpen, onClose, onSave, editingUser }) {
   useEffect(() => {
     const fetchMasterData = async () => {
       try {
-        const response = await fetch('/api/masterdata');
+        const response = await authFetch('/api/masterdata');
         if (response.ok) {
           const data = await response.json();
           
@@ -3,6 +3,7 @@ import { Dropdown, DropdownButton, DropdownMenu, DropdownItem, DropdownDivider }
 import { ChevronDownIcon } from '@heroicons/react/solid';
 import { Link, useNavigate } from 'react-router-dom';
 import UserProfilePopup from '../components/UserProfilePopup';
+import authFetch, { clearSession } from '../utils/authFetch';
 
 const ApproveNavbar = ({ onToggleSidebar }) => {
   const navigate = useNavigate();
@@ -20,10 +21,10 @@ const ApproveNavbar = ({ onToggleSidebar }) => {
     const fetchUserData = async () => {
       try {
         setLoading(true);
-        const userEmail = localStorage.getItem('userEmail');
+        const token = localStorage.getItem('jwt_token');
 
-        if (userEmail) {
-          const response = await fetch(`/api/user/profile?email=${userEmail}`);
+        if (token) {
+          const response = await authFetch('/api/me');
 
           if (response.ok) {
             const data = await response.json();
@@ -45,10 +46,8 @@ const ApproveNavbar = ({ onToggleSidebar }) => {
     fetchUserData();
   }, []);
 
-  const signOut = () => {
-    localStorage.removeItem('userEmail');
-    localStorage.removeItem('jwt_token');
-    localStorage.removeItem('user_role');
+  const signOut = async () => {
+    await clearSession();
     navigate('/login');
   };
 
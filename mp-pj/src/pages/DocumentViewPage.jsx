@@ -1,6 +1,6 @@
 import React, { useState, useEffect } from 'react';
 import { useParams, useNavigate } from 'react-router-dom';
-import { rawDocuments as mockApiData } from '../data/mockData';
+import authFetch from '../utils/authFetch';
 
 const FormField = ({ label, value }) => (
   <div>
@@ -15,6 +15,8 @@ const DocumentViewPage = () => {
   const { id } = useParams();
   const navigate = useNavigate();
   const [userRole, setUserRole] = useState('');
+  const [document, setDocument] = useState(null);
+  const [isLoading, setIsLoading] = useState(true);
 
   const [notification, setNotification] = useState({
     show: false,
@@ -29,7 +31,24 @@ const DocumentViewPage = () => {
     }
   }, []);
 
-  const document = mockApiData.find(doc => doc.id.toString() === id);
+  useEffect(() => {
+    const fetchDocument = async () => {
+      try {
+        const response = await authFetch(`/api/requests/${id}`);
+        if (response.ok) {
+          setDocument(await response.json());
+        } else {
+          setDocument(null);
+        }
+      } catch (error) {
+        console.error('Error fetching request:', error);
+        setDocument(null);
+      } finally {
+        setIsLoading(false);
+      }
+    };
+    fetchDocument();
+  }, [id]);
 
   const showNotification = (message, type = 'success') => {
     setNotification({ show: true, message, type });
@@ -39,14 +58,42 @@ const DocumentViewPage = () => {
     }, 1500);
   };
 
-  const handleApprove = () => {
-    showNotification('อนุมัติเอกสารเรียบร้อย', 'success');
+  const submitDecision = async (decision) => {
+    try {
+      const response = await authFetch(`/api/requests/${id}/decisions`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ decision })
+      });
+      const data = await response.json();
+      if (!response.ok) {
+        showNotification(data.error || 'เกิดข้อผิดพลาดในการบันทึกผลการพิจารณา', 'error');
+        return false;
+      }
+      return true;
+    } catch (error) {
+      console.error('Error submitting decision:', error);
+      showNotification('เกิดข้อผิดพลาดในการเชื่อมต่อ', 'error');
+      return false;
+    }
   };
 
-  const handleReject = () => {
-    showNotification('ไม่อนุมัติเอกสาร', 'error');
+  const handleApprove = async () => {
+    if (await submitDecision('approve')) {
+      showNotification('อนุมัติเอกสารเรียบร้อย', 'success');
+    }
   };
 
+  const handleReject = async () => {
+    if (await submitDecision('reject')) {
+      showNotification('ไม่อนุมัติเอกสาร', 'error');
+    }
+  };
+
+  if (isLoading) {
+    return <div className="p-8 text-center text-gray-500">กำลังโหลดข้อมูล...</div>;
+  }
+
   if (!document) {
     return (
       <div className="p-8 text-center">
@@ -73,7 +120,7 @@ const DocumentViewPage = () => {
             <div></div>
           </div>
           <div className="grid grid-cols-1 md:grid-cols-2 gap-6">
-            <FormField label="ฝ่าย" value={document.division} />
+            <FormField label="เลขที่เอกสาร" value={document.documentNumber} />
             <FormField label="แผนก" value={document.department} />
           </div>
           <div className="grid grid-cols-1 md:grid-cols-2 gap-6">
@@ -81,17 +128,50 @@ const DocumentViewPage = () => {
             <FormField label="ประเภทสัญญาจ้าง" value={document.contractType} />
           </div>
           <div className="grid grid-cols-1 md:grid-cols-2 gap-6">
-            <FormField label="เหตุผลที่ร้องขอ" value={document.reason} />
-            <FormField label="ชื่อผู้ร้องขอ" value={document.requester} />
+            <FormField label="เหตุผลที่ร้องขอ" value={document.requestReason} />
+            <FormField label="ชื่อผู้ร้องขอ" value={document.requesterName} />
           </div>
         </div>
+        <div className="mt-10 pt-6 border-t">
+          <h2 className="text-2xl font-bold text-gray-700 mb-6">ตำแหน่งที่ต้องการ</h2>
+          <table className="w-full text-left text-gray-700">
+            <thead>
+              <tr className="border-b text-sm text-gray-500">
+                <th className="py-2">#</th>
+                <th className="py-2">รหัสตำแหน่งงาน</th>
+                <th className="py-2">ตำแหน่ง</th>
+                <th className="py-2 text-center">จำนวน</th>
+                <th className="py-2 text-center">จัดหาแล้ว</th>
+                <th className="py-2">วันที่ต้องการให้เริ่มงาน</th>
+              </tr>
+            </thead>
+            <tbody>
+              {document.lines.map(line => (
+                <React.Fragment key={line.lineId}>
+                  <tr className="border-b">
+                    <td className="py-2">{line.lineNo}</td>
+                    <td className="py-2">{line.positionId || '--'}</td>
+                    <td className="py-2">{line.positionRequire || '--'}</td>
+                    <td className="py-2 text-center">{line.quantity}</td>
+                    <td className="py-2 text-center">{line.filledQuantity}/{line.quantity}</td>
+                    <td className="py-2">{line.targetHireDate || '--'}</td>
+                  </tr>
+                  {line.qualifications && (
+                    <tr className="border-b">
+                      <td></td>
+                      <td colSpan="5" className="py-2 text-sm text-gray-500">{line.qualifications}</td>
+                    </tr>
+                  )}
+                </React.Fragment>
+              ))}
+            </tbody>
+          </table>
+          <p className="text-sm text-gray-500 mt-2">รวม {document.headcount} อัตรา</p>
+        </div>
+
         <div className="mt-10 pt-6 border-t">
           <h2 className="text-2xl font-bold text-gray-700 mb-6">คุณสมบัติ</h2>
           <div className="space-y-6">
-            <div className="grid grid-cols-1 md:grid-cols-2 gap-6">
-              <FormField label="รหัสตำแหน่งงาน" value={document.jobCode} />
-              <FormField label="ตำแหน่งที่ต้องการ" value={document.positionRequired} />
-            </div>
             <div className="grid grid-cols-1 md:grid-cols-2 gap-6">
               <FormField label="อายุตั้งแต่ (ปี)" value={document.ageFrom} />
               <FormField label="ถึงอายุ (ปี)" value={document.ageTo} />
@@ -110,6 +190,27 @@ const DocumentViewPage = () => {
           </div>
         </div>
 
+        <div className="mt-10 pt-6 border-t">
+          <h2 className="text-2xl font-bold text-gray-700 mb-2">ลำดับการพิจารณา</h2>
+          <p className="text-sm text-gray-500 mb-6">สถานะปัจจุบัน: {document.currentStatus || '--'}</p>
+          {document.workflow.history.length === 0 ? (
+            <p className="text-gray-400">ยังไม่มีประวัติการพิจารณา</p>
+          ) : (
+            <ol className="space-y-4 border-l-2 border-gray-200 pl-6">
+              {document.workflow.history.map(entry => (
+                <li key={entry.historyId}>
+                  <p className="text-sm text-gray-400">{new Date(entry.approvalTime).toLocaleString('th-TH')}</p>
+                  <p className="text-gray-700">
+                    <span className="font-semibold">{entry.stepName}</span> · {entry.approverName} · {entry.decision}
+                    {entry.onBehalfOfName && <span className="text-gray-500"> (แทน {entry.onBehalfOfName})</span>}
+                  </p>
+                  {entry.notes && <p className="text-sm text-gray-500">{entry.notes}</p>}
+                </li>
+              ))}
+            </ol>
+          )}
+        </div>
+
         <div className="mt-12 flex justify-between items-center">
           <button
             onClick={() => navigate(-1)}
@@ -118,7 +219,7 @@ const DocumentViewPage = () => {
             กลับ
           </button>
 
-          {userRole === 'approve' && (
+          {userRole === 'approve' && document.lifecycleStatus === 'IN_APPROVAL' && (
             <div className="flex gap-4">
               <button
                 onClick={handleReject}
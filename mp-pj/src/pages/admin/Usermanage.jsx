@@ -5,6 +5,7 @@ import Pagination from '../../components/Pagination';
 import AddUserModal from '../../components/AddUserModal';
 import ConfirmationModal from '../../components/ConfirmationModal'; 
 import { SearchIcon, XIcon, PlusIcon } from '@heroicons/react/solid';
+import authFetch from '../../utils/authFetch';
 
 const ITEMS_PER_PAGE = 10;
 
@@ -33,7 +34,7 @@ function UserManage() {
   const fetchUsers = async () => {
     setLoading(true);
     try {
-      const response = await fetch('/api/admin/employees'); 
+      const response = await authFetch('/api/admin/employees');
       if (response.ok) {
         const data = await response.json();
         setUsers(data.map((user, index) => ({ 
@@ -131,7 +132,7 @@ function UserManage() {
     
     if (!isEditing) {
         try {
-            const response = await fetch(url, {
+            const response = await authFetch(url, {
                 method: 'POST',
                 headers: { 'Content-Type': 'application/json' },
                 body: JSON.stringify(dataToSubmit),
@@ -1,11 +1,13 @@
-import React, { useState, useEffect, useMemo, useRef } from 'react';
+import React, { useState, useEffect } from 'react';
 import UserStatusDropdown from '../../components/UserStatusDropdown';
 import UserListTable from '../../components/UserListTable';
 import Pagination from '../../components/Pagination';
-import { rawDocuments as mockApiData } from '../../data/mockData';
+import authFetch from '../../utils/authFetch';
+import { toListDocument, buildRequestQuery } from '../../utils/requestList';
 
 const AMainpage = () => {
   const [documents, setDocuments] = useState([]);
+  const [totalItems, setTotalItems] = useState(0);
   const [isLoading, setIsLoading] = useState(true);
   const [currentPage, setCurrentPage] = useState(1);
   const ITEMS_PER_PAGE = 10;
@@ -14,11 +16,29 @@ const AMainpage = () => {
   const [inputStatus, setInputStatus] = useState('');
 
   useEffect(() => {
-    setTimeout(() => {
-      setDocuments(mockApiData);
-      setIsLoading(false);
-    }, 1000);
-  }, []);
+    const fetchDocuments = async () => {
+      setIsLoading(true);
+      try {
+        const response = await authFetch(buildRequestQuery({
+          page: currentPage,
+          pageSize: ITEMS_PER_PAGE,
+          docNumber: inputDocNumber.trim(),
+          status: inputStatus,
+        }));
+        if (!response.ok) throw new Error('Failed to fetch requests');
+        const data = await response.json();
+        setDocuments(data.items.map(toListDocument));
+        setTotalItems(data.total);
+      } catch (error) {
+        console.error('Error fetching requests:', error);
+        setDocuments([]);
+        setTotalItems(0);
+      } finally {
+        setIsLoading(false);
+      }
+    };
+    fetchDocuments();
+  }, [currentPage, inputDocNumber, inputStatus]);
 
   const handleClearFilters = () => {
     setInputDocNumber('');
@@ -26,36 +46,17 @@ const AMainpage = () => {
     setCurrentPage(1);
   };
 
-  const filteredDocuments = useMemo(() => {
-    const trimmedSearch = inputDocNumber.trim().toLowerCase();
-    return documents.filter(doc => {
-      const statusMatch = inputStatus === '' ||
-        doc.managerStatus === inputStatus ||
-        doc.hrStatus === inputStatus ||
-        doc.ceoStatus === inputStatus;
-
-      const searchMatch = trimmedSearch === '' ||
-        doc.documentNumber.toLowerCase().includes(trimmedSearch);
-
-      return statusMatch && searchMatch;
-    });
-  }, [documents, inputDocNumber, inputStatus]);
-
   useEffect(() => {
     setCurrentPage(1);
   }, [inputDocNumber, inputStatus]);
 
+  const totalPages = Math.ceil(totalItems / ITEMS_PER_PAGE);
+  const indexOfFirstItem = (currentPage - 1) * ITEMS_PER_PAGE;
 
-  const totalPages = Math.ceil(filteredDocuments.length / ITEMS_PER_PAGE);
-  const indexOfLastItem = currentPage * ITEMS_PER_PAGE;
-  const indexOfFirstItem = indexOfLastItem - ITEMS_PER_PAGE;
-
-  const currentDocuments = filteredDocuments
-    .slice(indexOfFirstItem, indexOfLastItem)
-    .map((doc, index) => ({
-      ...doc,
-      itemNumber: indexOfFirstItem + index + 1,
-    }));
+  const currentDocuments = documents.map((doc, index) => ({
+    ...doc,
+    itemNumber: indexOfFirstItem + index + 1,
+  }));
 
   const handlePageChange = (pageNumber) => {
     setCurrentPage(pageNumber);
@@ -106,7 +107,7 @@ const AMainpage = () => {
         </div>
       </div>
  
-      {!isLoading && filteredDocuments.length === 0 ? (
+      {!isLoading && totalItems === 0 ? (
         <div className="text-center py-12 border-t border-gray-200 mt-4">
           <p className="text-gray-500 text-lg">ไม่พบเอกสารที่ค้นหา</p>
           <p className="text-gray-400 text-sm mt-2">กรุณาลองตรวจสอบเลขที่เอกสารหรือสถานะอีกครั้ง</p>
@@ -125,7 +126,7 @@ const AMainpage = () => {
                 currentPage={currentPage}
                 totalPages={totalPages}
                 onPageChange={handlePageChange}
-                totalItems={filteredDocuments.length}
+                totalItems={totalItems}
                 itemsOnPage={currentDocuments.length}
               />
             </div>
@@ -1,8 +1,22 @@
 import React, { useState, useEffect } from 'react';
-import { useNavigate } from 'react-router-dom'; 
+import { useNavigate, useSearchParams } from 'react-router-dom'; 
+import authFetch from '../../utils/authFetch';
+
+// ตำแหน่งที่ขอในใบร้องขอ หนึ่งใบขอได้หลายตำแหน่ง
+const EMPTY_LINE = {
+  positionId: '',
+  requiredPositionId: '',
+  quantity: '1',
+  qualifications: '',
+  targetHireDate: ''
+};
 
 const UserRForm = () => {
   const navigate = useNavigate(); 
+  const [searchParams] = useSearchParams();
+  const [draftId, setDraftId] = useState(searchParams.get('draft'));
+  const [fieldErrors, setFieldErrors] = useState({});
+  const [lines, setLines] = useState([{ ...EMPTY_LINE }]);
 
   const [formData, setFormData] = useState({
     documentDate: '',
@@ -12,8 +26,6 @@ const UserRForm = () => {
     contractTypeId: '',
     requestReasonId: '',
     requesterName: '',
-    positionId: '',
-    requiredPositionId: '',
     ageFrom: '',
     ageTo: '',
     genderId: '',
@@ -48,6 +60,8 @@ const UserRForm = () => {
     return item ? item.name : '';
   };
 
+  const idOrEmpty = (id) => (id ? String(id) : '');
+
   useEffect(() => {
     const today = new Date();
     const day = String(today.getDate()).padStart(2, '0');
@@ -58,7 +72,7 @@ const UserRForm = () => {
 
     const fetchMasterData = async () => {
       try {
-        const response = await fetch('/api/masterdata');
+        const response = await authFetch('/api/masterdata');
         if (response.ok) {
           const data = await response.json();
           setMasterData(data);
@@ -74,11 +88,123 @@ const UserRForm = () => {
     fetchMasterData();
   }, []);
 
+  useEffect(() => {
+    const initialDraftId = searchParams.get('draft');
+    if (!initialDraftId) return;
+
+    const fetchDraft = async () => {
+      try {
+        const response = await authFetch(`/api/requests/drafts/${initialDraftId}`);
+        if (!response.ok) {
+          setDraftId(null);
+          showNotification('ไม่พบฉบับร่าง', 'error');
+          return;
+        }
+        const draft = await response.json();
+        setFormData(prev => ({
+          ...prev,
+          documentDate: draft.documentDate || prev.documentDate,
+          departmentId: idOrEmpty(draft.departmentId),
+          employmentTypeId: idOrEmpty(draft.employmentTypeId),
+          contractTypeId: idOrEmpty(draft.contractTypeId),
+          requestReasonId: idOrEmpty(draft.requestReasonId),
+          ageFrom: draft.ageFrom,
+          ageTo: draft.ageTo,
+          genderId: idOrEmpty(draft.genderId),
+          nationalityId: idOrEmpty(draft.nationalityId),
+          experienceId: idOrEmpty(draft.experienceId),
+          educationLevelId: idOrEmpty(draft.educationLevelId),
+          specialQualifications: draft.specialQualifications
+        }));
+        if (draft.lines && draft.lines.length > 0) {
+          setLines(draft.lines.map(line => ({
+            positionId: line.positionId,
+            requiredPositionId: idOrEmpty(line.positionRequireId),
+            quantity: String(line.quantity),
+            qualifications: line.qualifications,
+            targetHireDate: line.targetHireDate
+          })));
+        }
+      } catch (error) {
+        console.error('Error fetching draft:', error);
+        showNotification('เกิดข้อผิดพลาดในการเชื่อมต่อเพื่อดึงฉบับร่าง', 'error');
+      }
+    };
+    fetchDraft();
+  }, []);
+
   const handleChange = (e) => {
     const { name, value } = e.target;
     setFormData(prev => ({ ...prev, [name]: value }));
+    setFieldErrors(prev => ({ ...prev, [name]: undefined }));
+  };
+
+  const handleLineChange = (index, e) => {
+    const { name, value } = e.target;
+    setLines(prev => prev.map((line, i) => (i === index ? { ...line, [name]: value } : line)));
+    setFieldErrors(prev => ({ ...prev, [`lines.${index}.${name}`]: undefined }));
+  };
+
+  const addLine = () => setLines(prev => [...prev, { ...EMPTY_LINE }]);
+
+  const removeLine = (index) => {
+    setLines(prev => prev.filter((_, i) => i !== index));
+    setFieldErrors({});
+  };
+
+  // ชื่อฟิลด์ที่ backend ส่งกลับมา -> ชื่อฟิลด์ในฟอร์ม
+  const PAYLOAD_FIELDS = {
+    documentDate: 'documentDate',
+    department: 'departmentId',
+    employmentType: 'employmentTypeId',
+    contractType: 'contractTypeId',
+    requestReason: 'requestReasonId',
+    ageFrom: 'ageFrom',
+    ageTo: 'ageTo',
+    gender: 'genderId',
+    nationality: 'nationalityId',
+    experience: 'experienceId',
+    educationLevel: 'educationLevelId',
+    departmentId: 'departmentId',
+    employmentTypeId: 'employmentTypeId',
+    contractTypeId: 'contractTypeId',
+    requestReasonId: 'requestReasonId',
+    genderId: 'genderId',
+    nationalityId: 'nationalityId',
+    experienceId: 'experienceId',
+    educationLevelId: 'educationLevelId',
+  };
+
+  const LINE_FIELDS = {
+    positionRequire: 'requiredPositionId',
+    positionRequireId: 'requiredPositionId',
+  };
+
+  const formFieldOf = (field) => {
+    const match = /^lines\[(\d+)\]\.(\w+)$/.exec(field);
+    if (match) return `lines.${match[1]}.${LINE_FIELDS[match[2]] || match[2]}`;
+    return PAYLOAD_FIELDS[field] || field;
+  };
+
+  const applyFieldErrors = (fields) => {
+    const errors = {};
+    fields.forEach(f => {
+      errors[formFieldOf(f.field)] = f.messageTh || f.message;
+    });
+    setFieldErrors(errors);
+  };
+
+  // Validation failures carry a message per field; the first one is shown in
+  // the notification and the rest are marked on the form.
+  const errorMessageOf = (data, fallback) => {
+    const first = data.fields && data.fields[0];
+    if (first) return first.messageTh || first.message;
+    return data.message || data.error || fallback;
   };
 
+  const inputClass = (name, extra = '') =>
+    `w-full px-4 py-3 border-2 ${fieldErrors[name] ? 'border-red-500' : 'border-gray-300'} rounded-lg focus:outline-none focus:border-purple-500 focus:ring-2 focus:ring-purple-200 transition-all${extra}`;
+
   const handleDateChange = (e) => {
     let value = e.target.value.replace(/\D/g, '');
 
@@ -90,6 +216,7 @@ const UserRForm = () => {
     }
 
     setFormData(prev => ({ ...prev, documentDate: value }));
+    setFieldErrors(prev => ({ ...prev, documentDate: undefined }));
   };
 
   const showNotification = (message, type = 'success') => {
@@ -112,8 +239,6 @@ const UserRForm = () => {
       contractTypeId: '',
       requestReasonId: '',
       requesterName: '',
-      positionId: '',
-      requiredPositionId: '',
       ageFrom: '',
       ageTo: '',
       genderId: '',
@@ -122,21 +247,13 @@ const UserRForm = () => {
       educationLevelId: '',
       specialQualifications: ''
     });
+    setLines([{ ...EMPTY_LINE }]);
+    setDraftId(null);
+    setFieldErrors({});
     showNotification('เริ่มต้นฟอร์มใหม่', 'success');
   };
 
-  const handleSubmit = async (e) => {
-    e.preventDefault();
-
-    if (formData.ageFrom && formData.ageTo) {
-      if (parseInt(formData.ageFrom) > parseInt(formData.ageTo)) {
-        showNotification('อายุเริ่มต้นต้องน้อยกว่าหรือเท่ากับอายุสิ้นสุด', 'error');
-        console.error('Validation Error: อายุไม่ถูกต้อง');
-        return;
-      }
-    }
-
-    const dataToSubmit = {
+  const buildPayload = () => ({
       documentDate: formData.documentDate,
       department: getNameFromId(formData.departmentId, 'departments'),
       section: getNameFromId(formData.sectionId, 'sections'),
@@ -144,8 +261,6 @@ const UserRForm = () => {
       contractType: getNameFromId(formData.contractTypeId, 'contractTypes'),
       requestReason: getNameFromId(formData.requestReasonId, 'requestReasons'),
       requesterName: formData.requesterName,
-      positionId: formData.positionId,
-      positionRequire: getNameFromId(formData.requiredPositionId, 'positions'),
       ageFrom: formData.ageFrom,
       ageTo: formData.ageTo,
       gender: getNameFromId(formData.genderId, 'genders'),
@@ -153,28 +268,89 @@ const UserRForm = () => {
       experience: getNameFromId(formData.experienceId, 'experiences'),
       educationLevel: getNameFromId(formData.educationLevelId, 'educationLevels'),
       specialQualifications: formData.specialQualifications,
-    };
+      departmentId: parseInt(formData.departmentId) || 0,
+      employmentTypeId: parseInt(formData.employmentTypeId) || 0,
+      contractTypeId: parseInt(formData.contractTypeId) || 0,
+      requestReasonId: parseInt(formData.requestReasonId) || 0,
+      genderId: parseInt(formData.genderId) || 0,
+      nationalityId: parseInt(formData.nationalityId) || 0,
+      experienceId: parseInt(formData.experienceId) || 0,
+      educationLevelId: parseInt(formData.educationLevelId) || 0,
+      lines: lines.map(line => ({
+        positionId: line.positionId,
+        positionRequire: getNameFromId(line.requiredPositionId, 'positions'),
+        positionRequireId: parseInt(line.requiredPositionId) || 0,
+        quantity: parseInt(line.quantity) || 0,
+        qualifications: line.qualifications,
+        targetHireDate: line.targetHireDate
+      })),
+  });
+
+  const saveDraft = async () => {
+    const response = await authFetch(draftId ? `/api/requests/drafts/${draftId}` : '/api/requests/drafts', {
+      method: draftId ? 'PUT' : 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify(buildPayload())
+    });
+    const data = await response.json();
+    if (!response.ok || !data.success) {
+      if (data.fields) applyFieldErrors(data.fields);
+      throw new Error(errorMessageOf(data, 'เกิดข้อผิดพลาดในการบันทึกฉบับร่าง'));
+    }
+    setDraftId(String(data.draftId));
+    return data.draftId;
+  };
+
+  const handleSaveDraft = async () => {
+    try {
+      await saveDraft();
+      showNotification('บันทึกฉบับร่างเรียบร้อย', 'success');
+    } catch (error) {
+      console.error('บันทึกฉบับร่างไม่สำเร็จ:', error);
+      showNotification('เกิดข้อผิดพลาด: ' + error.message, 'error');
+    }
+  };
 
+  const handleSubmit = async (e) => {
+    e.preventDefault();
+
+    if (formData.ageFrom && formData.ageTo) {
+      if (parseInt(formData.ageFrom) > parseInt(formData.ageTo)) {
+        showNotification('อายุเริ่มต้นต้องน้อยกว่าหรือเท่ากับอายุสิ้นสุด', 'error');
+        console.error('Validation Error: อายุไม่ถูกต้อง');
+        return;
+      }
+    }
+
+    const dataToSubmit = buildPayload();
 
     console.log('กำลังส่งข้อมูล:', dataToSubmit);
 
     try {
-      const response = await fetch('/api/request', {
-        method: 'POST',
-        headers: { 'Content-Type': 'application/json' },
-        body: JSON.stringify(dataToSubmit)
-      });
+      let response;
+      if (draftId) {
+        const savedDraftId = await saveDraft();
+        response = await authFetch(`/api/requests/drafts/${savedDraftId}/submit`, { method: 'POST' });
+      } else {
+        response = await authFetch('/api/request', {
+          method: 'POST',
+          headers: { 'Content-Type': 'application/json' },
+          body: JSON.stringify(dataToSubmit)
+        });
+      }
 
       const data = await response.json();
 
       console.log('Response จาก Server:', data);
 
       if (response.ok && data.success) {
+        setFieldErrors({});
         console.log('บันทึกข้อมูลสำเร็จ ID:', data.id);
         showNotification('บันทึกข้อมูลเสร็จสิ้น', 'success');
         setTimeout(() => handleClear(), 1500);
       } else {
-        const errorMessage = data.message || data.error || 'เกิดข้อผิดพลาดในการบันทึกข้อมูล';
+        if (data.fields) applyFieldErrors(data.fields);
+        const errorMessage = errorMessageOf(data, 'เกิดข้อผิดพลาดในการบันทึกข้อมูล');
         console.error('บันทึกไม่สำเร็จ:', errorMessage);
         showNotification('เกิดข้อผิดพลาด: ' + errorMessage, 'error');
       }
@@ -203,7 +379,7 @@ const UserRForm = () => {
                 placeholder="DD/MM/YYYY"
                 maxLength="10"
                 required
-                className="w-64 max-w-full px-4 py-3 border-2 border-gray-300 rounded-lg focus:outline-none focus:border-purple-500 focus:ring-2 focus:ring-purple-200 transition-all"
+                className={`w-64 max-w-full px-4 py-3 border-2 ${fieldErrors.documentDate ? 'border-red-500' : 'border-gray-300'} rounded-lg focus:outline-none focus:border-purple-500 focus:ring-2 focus:ring-purple-200 transition-all`}
               />
             </div>
           </div>
@@ -216,7 +392,7 @@ const UserRForm = () => {
                 value={formData.departmentId}
                 onChange={handleChange}
                 required
-                className="w-full px-4 py-3 border-2 border-gray-300 rounded-lg focus:outline-none focus:border-purple-500 focus:ring-2 focus:ring-purple-200 transition-all bg-white"
+                className={inputClass('departmentId', ' bg-white')}
               >
                 <option value="">-- เลือกฝ่าย --</option>
                 {masterData.departments.map((dept) => (
@@ -232,7 +408,7 @@ const UserRForm = () => {
                 value={formData.sectionId}
                 onChange={handleChange}
                 required
-                className="w-full px-4 py-3 border-2 border-gray-300 rounded-lg focus:outline-none focus:border-purple-500 focus:ring-2 focus:ring-purple-200 transition-all bg-white"
+                className={inputClass('sectionId', ' bg-white')}
               >
                 <option value="">-- เลือกแผนก --</option>
                 {masterData.sections.map((section) => (
@@ -250,7 +426,7 @@ const UserRForm = () => {
                 value={formData.employmentTypeId}
                 onChange={handleChange}
                 required
-                className="w-full px-4 py-3 border-2 border-gray-300 rounded-lg focus:outline-none focus:border-purple-500 focus:ring-2 focus:ring-purple-200 transition-all bg-white"
+                className={inputClass('employmentTypeId', ' bg-white')}
               >
                 <option value="">-- เลือกประเภทการจ้าง --</option>
                 {masterData.employmentTypes.map((type) => (
@@ -266,7 +442,7 @@ const UserRForm = () => {
                 value={formData.contractTypeId}
                 onChange={handleChange}
                 required
-                className="w-full px-4 py-3 border-2 border-gray-300 rounded-lg focus:outline-none focus:border-purple-500 focus:ring-2 focus:ring-purple-200 transition-all bg-white"
+                className={inputClass('contractTypeId', ' bg-white')}
               >
                 <option value="">-- เลือกประเภทสัญญา --</option>
                 {masterData.contractTypes.map((type) => (
@@ -284,7 +460,7 @@ const UserRForm = () => {
                 value={formData.requestReasonId}
                 onChange={handleChange}
                 required
-                className="w-full px-4 py-3 border-2 border-gray-300 rounded-lg focus:outline-none focus:border-purple-500 focus:ring-2 focus:ring-purple-200 transition-all bg-white"
+                className={inputClass('requestReasonId', ' bg-white')}
               >
                 <option value="">-- เลือกเหตุผล --</option>
                 {masterData.requestReasons.map((reason) => (
@@ -302,44 +478,114 @@ const UserRForm = () => {
                 onChange={handleChange}
                 placeholder=""
                 required
-                className="w-full px-4 py-3 border-2 border-gray-300 rounded-lg focus:outline-none focus:border-purple-500 focus:ring-2 focus:ring-purple-200 transition-all"
+                className={inputClass('requesterName')}
               />
             </div>
           </div>
 
           <hr className="my-12 h-0.5 border-t-0 bg-neutral-200 opacity-100" />
 
-          <div className="grid grid-cols-1 md:grid-cols-2 gap-6">
-            <div>
-              <label className="block text-sm font-semibold text-gray-700 mb-2">รหัสตำแหน่งงาน</label>
-              <input
-                type="text"
-                name="positionId"
-                value={formData.positionId}
-                onChange={handleChange}
-                placeholder=""
-                required
-                className="w-full px-4 py-3 border-2 border-gray-300 rounded-lg focus:outline-none focus:border-purple-500 focus:ring-2 focus:ring-purple-200 transition-all"
-              />
-            </div>
-
-            <div>
-              <label className="block text-sm font-semibold text-gray-700 mb-2">ตำแหน่งที่ต้องการ</label>
-              <select
-                name="requiredPositionId"
-                value={formData.requiredPositionId}
-                onChange={handleChange}
-                required
-                className="w-full px-4 py-3 border-2 border-gray-300 rounded-lg focus:outline-none focus:border-purple-500 focus:ring-2 focus:ring-purple-200 transition-all bg-white"
-              >
-                <option value="">-- เลือกตำแหน่ง --</option>
-                {masterData.positions.map((pos) => (
-                  <option key={pos.id} value={pos.id}>{pos.name}</option>
-                ))}
-              </select>
-            </div>
+          <div className="flex justify-between items-center">
+            <h3 className="text-xl font-bold text-gray-800">ตำแหน่งที่ต้องการ</h3>
+            <button
+              type="button"
+              onClick={addLine}
+              className="bg-purple-500 hover:bg-purple-600 text-white font-medium py-2 px-6 rounded-lg shadow transition-all"
+            >
+              + เพิ่มตำแหน่ง
+            </button>
           </div>
 
+          {lines.map((line, index) => {
+            const lineClass = (name, extra = '') => inputClass(`lines.${index}.${name}`, extra);
+            return (
+              <div key={index} className="p-5 border-2 border-gray-200 rounded-lg space-y-4">
+                <div className="flex justify-between items-center">
+                  <span className="font-semibold text-gray-700">รายการที่ {index + 1}</span>
+                  {lines.length > 1 && (
+                    <button
+                      type="button"
+                      onClick={() => removeLine(index)}
+                      className="text-red-500 hover:text-red-700 font-medium"
+                    >
+                      ลบรายการ
+                    </button>
+                  )}
+                </div>
+
+                <div className="grid grid-cols-1 md:grid-cols-2 gap-6">
+                  <div>
+                    <label className="block text-sm font-semibold text-gray-700 mb-2">รหัสตำแหน่งงาน</label>
+                    <input
+                      type="text"
+                      name="positionId"
+                      value={line.positionId}
+                      onChange={(e) => handleLineChange(index, e)}
+                      required
+                      className={lineClass('positionId')}
+                    />
+                  </div>
+
+                  <div>
+                    <label className="block text-sm font-semibold text-gray-700 mb-2">ตำแหน่งที่ต้องการ</label>
+                    <select
+                      name="requiredPositionId"
+                      value={line.requiredPositionId}
+                      onChange={(e) => handleLineChange(index, e)}
+                      required
+                      className={lineClass('requiredPositionId', ' bg-white')}
+                    >
+                      <option value="">-- เลือกตำแหน่ง --</option>
+                      {masterData.positions.map((pos) => (
+                        <option key={pos.id} value={pos.id}>{pos.name}</option>
+                      ))}
+                    </select>
+                  </div>
+                </div>
+
+                <div className="grid grid-cols-1 md:grid-cols-2 gap-6">
+                  <div>
+                    <label className="block text-sm font-semibold text-gray-700 mb-2">จำนวน (คน)</label>
+                    <input
+                      type="number"
+                      name="quantity"
+                      value={line.quantity}
+                      onChange={(e) => handleLineChange(index, e)}
+                      min="1"
+                      max="999"
+                      required
+                      className={lineClass('quantity')}
+                    />
+                  </div>
+
+                  <div>
+                    <label className="block text-sm font-semibold text-gray-700 mb-2">วันที่ต้องการให้เริ่มงาน</label>
+                    <input
+                      type="text"
+                      name="targetHireDate"
+                      value={line.targetHireDate}
+                      onChange={(e) => handleLineChange(index, e)}
+                      placeholder="DD/MM/YYYY"
+                      maxLength="10"
+                      className={lineClass('targetHireDate')}
+                    />
+                  </div>
+                </div>
+
+                <div>
+                  <label className="block text-sm font-semibold text-gray-700 mb-2">คุณสมบัติเฉพาะตำแหน่ง</label>
+                  <textarea
+                    name="qualifications"
+                    value={line.qualifications}
+                    onChange={(e) => handleLineChange(index, e)}
+                    rows="2"
+                    className={lineClass('qualifications', ' resize-none')}
+                  />
+                </div>
+              </div>
+            );
+          })}
+
           <hr className="my-12 h-0.5 border-t-0 bg-neutral-200 opacity-100" />
 
           <div className="mb-6">
@@ -358,7 +604,7 @@ const UserRForm = () => {
                 min="15"
                 max="100"
                 required
-                className="w-full px-4 py-3 border-2 border-gray-300 rounded-lg focus:outline-none focus:border-purple-500 focus:ring-2 focus:ring-purple-200 transition-all"
+                className={inputClass('ageFrom')}
               />
             </div>
 
@@ -373,7 +619,7 @@ const UserRForm = () => {
                 min="15"
                 max="100"
                 required
-                className="w-full px-4 py-3 border-2 border-gray-300 rounded-lg focus:outline-none focus:border-purple-500 focus:ring-2 focus:ring-purple-200 transition-all"
+                className={inputClass('ageTo')}
               />
             </div>
           </div>
@@ -386,7 +632,7 @@ const UserRForm = () => {
                 value={formData.genderId}
                 onChange={handleChange}
                 required
-                className="w-full px-4 py-3 border-2 border-gray-300 rounded-lg focus:outline-none focus:border-purple-500 focus:ring-2 focus:ring-purple-200 transition-all bg-white"
+                className={inputClass('genderId', ' bg-white')}
               >
                 <option value="">-- เลือกเพศ --</option>
                 {masterData.genders.map((gender) => (
@@ -402,7 +648,7 @@ const UserRForm = () => {
                 value={formData.nationalityId}
                 onChange={handleChange}
                 required
-                className="w-full px-4 py-3 border-2 border-gray-300 rounded-lg focus:outline-none focus:border-purple-500 focus:ring-2 focus:ring-purple-200 transition-all bg-white"
+                className={inputClass('nationalityId', ' bg-white')}
               >
                 <option value="">-- เลือกสัญชาติ --</option>
                 {masterData.nationalities.map((nationality) => (
@@ -420,7 +666,7 @@ const UserRForm = () => {
                 value={formData.experienceId}
                 onChange={handleChange}
                 required
-                className="w-full px-4 py-3 border-2 border-gray-300 rounded-lg focus:outline-none focus:border-purple-500 focus:ring-2 focus:ring-purple-200 transition-all bg-white"
+                className={inputClass('experienceId', ' bg-white')}
               >
                 <option value="">-- เลือกประสบการณ์ --</option>
                 {masterData.experiences.map((exp) => (
@@ -436,7 +682,7 @@ const UserRForm = () => {
                 value={formData.educationLevelId}
                 onChange={handleChange}
                 required
-                className="w-full px-4 py-3 border-2 border-gray-300 rounded-lg focus:outline-none focus:border-purple-500 focus:ring-2 focus:ring-purple-200 transition-all bg-white"
+                className={inputClass('educationLevelId', ' bg-white')}
               >
                 <option value="">-- เลือกระดับการศึกษา --</option>
                 {masterData.educationLevels.map((level) => (
@@ -470,6 +716,13 @@ const UserRForm = () => {
               ย้อนกลับ
             </button>
             <div className="flex gap-4">
+              <button
+                type="button"
+                onClick={handleSaveDraft}
+                className="bg-yellow-500 hover:bg-yellow-600 text-white font-bold py-3 px-10 rounded-lg shadow-lg hover:shadow-xl transform hover:-translate-y-1 transition-all duration-300"
+              >
+                บันทึกฉบับร่าง
+              </button>
               <button
                 type="submit"
                 className="flex items-center gap-2 bg-blue-500 hover:bg-blue-600 text-white font-medium text-white py-3 px-10 rounded-lg shadow-lg hover:shadow-xl transform hover:-translate-y-1 transition-all duration-300"
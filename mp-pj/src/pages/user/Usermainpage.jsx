@@ -1,13 +1,15 @@
-import React, { useState, useEffect, useMemo } from 'react';
+import React, { useState, useEffect, useCallback } from 'react';
 import { Link } from 'react-router-dom';
 import UserStatusDropdown from '../../components/UserStatusDropdown';
 import UserListTable from '../../components/UserListTable';
 import Pagination from '../../components/Pagination'; // <<< ตรวจสอบว่าใช้ Component นี้
-import { rawDocuments as mockApiData } from '../../data/mockData';
+import authFetch from '../../utils/authFetch';
+import { toListDocument, buildRequestQuery } from '../../utils/requestList';
 import { PlusIcon } from '@heroicons/react/solid';
 
 const Usermainpage = () => {
   const [documents, setDocuments] = useState([]);
+  const [totalItems, setTotalItems] = useState(0);
   const [isLoading, setIsLoading] = useState(true);
   const [currentPage, setCurrentPage] = useState(1);
   const ITEMS_PER_PAGE = 10;
@@ -15,12 +17,31 @@ const Usermainpage = () => {
   const [filterDocNumber, setFilterDocNumber] = useState('');
   const [filterStatus, setFilterStatus] = useState('');
 
-  useEffect(() => {
-    setTimeout(() => {
-      setDocuments(mockApiData);
+  const fetchDocuments = useCallback(async () => {
+    setIsLoading(true);
+    try {
+      const response = await authFetch(buildRequestQuery({
+        page: currentPage,
+        pageSize: ITEMS_PER_PAGE,
+        docNumber: filterDocNumber.trim(),
+        status: filterStatus,
+      }));
+      if (!response.ok) throw new Error('Failed to fetch requests');
+      const data = await response.json();
+      setDocuments(data.items.map(toListDocument));
+      setTotalItems(data.total);
+    } catch (error) {
+      console.error('Error fetching requests:', error);
+      setDocuments([]);
+      setTotalItems(0);
+    } finally {
       setIsLoading(false);
-    }, 1000);
-  }, []);
+    }
+  }, [currentPage, filterDocNumber, filterStatus]);
+
+  useEffect(() => {
+    fetchDocuments();
+  }, [fetchDocuments]);
 
   const handleDocNumberChange = (e) => {
     setFilterDocNumber(e.target.value);
@@ -38,50 +59,37 @@ const Usermainpage = () => {
     setCurrentPage(1);
   };
 
-  const MOCK_CURRENT_USER = {
-    username: "somchai.j",
-    department: "การตลาด"
-  };
-
-  const filteredDocuments = useMemo(() => {
-    let filtered = documents.filter(doc => doc.department === MOCK_CURRENT_USER.department);
-
-    const lowerCaseFilter = filterDocNumber.toLowerCase();
-
-    if (lowerCaseFilter) {
-      filtered = filtered.filter(doc => doc.documentNumber.toLowerCase().endsWith(lowerCaseFilter));
-    } else if (filterStatus) {
-      filtered = filtered.filter(doc =>
-        doc.managerStatus === filterStatus ||
-        doc.hrStatus === filterStatus ||
-        doc.ceoStatus === filterStatus
-      );
-    }
-
-    return filtered;
-  }, [documents, filterDocNumber, filterStatus]);
-
-  const totalPages = Math.ceil(filteredDocuments.length / ITEMS_PER_PAGE);
-  const indexOfLastItem = currentPage * ITEMS_PER_PAGE;
-  const indexOfFirstItem = indexOfLastItem - ITEMS_PER_PAGE;
+  const totalPages = Math.ceil(totalItems / ITEMS_PER_PAGE);
+  const indexOfFirstItem = (currentPage - 1) * ITEMS_PER_PAGE;
 
-  const currentDocuments = filteredDocuments
-    .slice(indexOfFirstItem, indexOfLastItem)
-    .map((doc, index) => ({
-      ...doc,
-      itemNumber: indexOfFirstItem + index + 1,
-    }));
+  const currentDocuments = documents.map((doc, index) => ({
+    ...doc,
+    itemNumber: indexOfFirstItem + index + 1,
+  }));
 
   const handlePageChange = (pageNumber) => {
     setCurrentPage(pageNumber);
   };
 
-  const handleDelete = (documentId, documentNumber) => {
-    if (window.confirm(`คุณต้องการลบเอกสารเลขที่ "${documentNumber}" ใช่หรือไม่?`)) {
-      setDocuments(currentDocuments =>
-        currentDocuments.filter(doc => doc.id !== documentId)
-      );
-      alert(`เอกสาร "${documentNumber}" ถูกลบเรียบร้อยแล้ว`);
+  const handleDelete = async (documentId, documentNumber) => {
+    if (!window.confirm(`คุณต้องการยกเลิกเอกสารเลขที่ "${documentNumber}" ใช่หรือไม่?`)) return;
+
+    try {
+      const response = await authFetch(`/api/requests/${documentId}/withdraw`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({}),
+      });
+      const data = await response.json();
+      if (!response.ok) {
+        alert(`ไม่สามารถยกเลิกเอกสารได้: ${data.error || ''}`);
+        return;
+      }
+      alert(`เอกสาร "${documentNumber}" ถูกยกเลิกเรียบร้อยแล้ว`);
+      fetchDocuments();
+    } catch (error) {
+      console.error('Error withdrawing request:', error);
+      alert('เกิดข้อผิดพลาดในการเชื่อมต่อ');
     }
   };
 
@@ -136,7 +144,7 @@ const Usermainpage = () => {
         </div>
       </div>
 
-      { !isLoading && filteredDocuments.length === 0 ? (
+      { !isLoading && totalItems === 0 ? (
         <div className="text-center py-12 border-t border-gray-200 mt-4">
           <p className="text-gray-500 text-lg">ไม่พบเอกสารที่ค้นหา</p>
           <p className="text-gray-400 text-sm mt-2">กรุณาลองตรวจสอบเลขที่เอกสารหรือสถานะอีกครั้ง</p>
@@ -155,7 +163,7 @@ const Usermainpage = () => {
               currentPage={currentPage}
               totalPages={totalPages}
               onPageChange={handlePageChange}
-              totalItems={filteredDocuments.length} 
+              totalItems={totalItems} 
               itemsOnPage={currentDocuments.length} 
             />
           </div>
@@ -0,0 +1,57 @@
+let refreshPromise = null;
+
+const refreshAccessToken = async () => {
+  const refreshToken = localStorage.getItem('refresh_token');
+  if (!refreshToken) return false;
+
+  const response = await fetch('/api/refresh', {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ refreshToken }),
+  });
+  if (!response.ok) return false;
+
+  const data = await response.json();
+  localStorage.setItem('jwt_token', data.token);
+  localStorage.setItem('refresh_token', data.refreshToken);
+  return true;
+};
+
+const withAuthHeader = (options = {}) => ({
+  ...options,
+  headers: {
+    ...(options.headers || {}),
+    Authorization: `Bearer ${localStorage.getItem('jwt_token')}`,
+  },
+});
+
+const authFetch = async (url, options = {}) => {
+  const response = await fetch(url, withAuthHeader(options));
+  if (response.status !== 401) return response;
+
+  if (!refreshPromise) {
+    refreshPromise = refreshAccessToken().finally(() => {
+      refreshPromise = null;
+    });
+  }
+  const refreshed = await refreshPromise;
+  if (!refreshed) return response;
+
+  return fetch(url, withAuthHeader(options));
+};
+
+export const clearSession = async () => {
+  if (localStorage.getItem('jwt_token')) {
+    try {
+      await fetch('/api/logout', withAuthHeader({ method: 'POST' }));
+    } catch (error) {
+      console.error('Error during logout:', error);
+    }
+  }
+  localStorage.removeItem('userEmail');
+  localStorage.removeItem('jwt_token');
+  localStorage.removeItem('refresh_token');
+  localStorage.removeItem('user_role');
+};
+
+export default authFetch;
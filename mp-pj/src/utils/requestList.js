@@ -0,0 +1,38 @@
+// แปลงข้อมูลคำร้องจาก GET /api/requests ให้อยู่ในรูปที่ UserListTable ใช้
+
+const STEP_STATUS_LABELS = {
+  Approved: 'ผ่านการอนุมัติ',
+  Pending: 'รออนุมัติ',
+  Waiting: 'รออนุมัติ',
+  Rejected: 'ไม่อนุมัติ',
+  Returned: 'ส่งกลับแก้ไข',
+  Skipped: '-',
+};
+
+// ตัวเลือกใน UserStatusDropdown -> lifecycle status ของ backend
+export const STATUS_FILTERS = {
+  'ผ่านการอนุมัติ': 'APPROVED',
+  'รออนุมัติ': 'IN_APPROVAL',
+  'ไม่อนุมัติ': 'REJECTED',
+};
+
+const stepLabel = (item, stepCode) => STEP_STATUS_LABELS[item.stepStatuses[stepCode]] || '-';
+
+export const toListDocument = (item) => ({
+  id: item.requestId,
+  documentNumber: item.documentNumber,
+  documentDate: item.documentDate,
+  department: item.department,
+  managerStatus: stepLabel(item, 'MANAGER'),
+  hrStatus: stepLabel(item, 'HR'),
+  ceoStatus: stepLabel(item, 'CEO'),
+  dueDate: item.dueAt ? new Date(item.dueAt).toLocaleDateString('en-GB') : '-',
+});
+
+export const buildRequestQuery = ({ page, pageSize, docNumber, status, awaitingMe }) => {
+  const params = new URLSearchParams({ page, pageSize });
+  if (docNumber) params.set('docNumber', docNumber);
+  if (status && STATUS_FILTERS[status]) params.set('status', STATUS_FILTERS[status]);
+  if (awaitingMe) params.set('awaitingMe', 'true');
+  return `/api/requests?${params.toString()}`;
+};
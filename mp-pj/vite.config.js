@@ -9,6 +9,9 @@ export default defineConfig({
       '/api': {
         target: 'http://backend:8080',
         changeOrigin: true,
+        // Pass the browser's address on; the backend trusts it from the
+        // proxies listed in TRUSTED_PROXIES.
+        xfwd: true,
       }
     }
   }